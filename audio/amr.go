@@ -0,0 +1,44 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// amrWBMagic starts AMR-WB files in the storage format of RFC 4867.
+const amrWBMagic = "#!AMR-WB\n"
+
+// AMR-WB frames hold 20 ms of audio sampled at 16 kHz.
+const (
+	amrWBFrameDuration = 20 * time.Millisecond
+	amrWBSampleRate    = 16000
+)
+
+// amrWBFrameSizes are the sizes in bytes of AMR-WB frames, including their table of contents byte, by frame
+// type: the nine speech modes from 6.6 to 23.85 kbit/s, the comfort noise frame, four reserved types (zero)
+// and the frame types of lost speech and of no data.
+var amrWBFrameSizes = [16]int{18, 24, 33, 37, 41, 47, 51, 59, 61, 6, 0, 0, 0, 0, 1, 1}
+
+// probeAMRWB counts the frames of an AMR-WB file, whose header is optional.
+func probeAMRWB(data []byte) (Info, error) {
+	data = bytes.TrimPrefix(data, []byte(amrWBMagic))
+	frames := 0
+	for len(data) > 0 {
+		frameType := data[0] >> 3 & 0x0F
+		size := amrWBFrameSizes[frameType]
+		if size == 0 {
+			return Info{}, fmt.Errorf("AMR-WB frame %d has the reserved frame type %d", frames, frameType)
+		}
+		if size > len(data) {
+			// a truncated last frame is not played.
+			break
+		}
+		data = data[size:]
+		frames++
+	}
+	if frames == 0 {
+		return Info{}, fmt.Errorf("audio has no AMR-WB frames")
+	}
+	return Info{Duration: time.Duration(frames) * amrWBFrameDuration, SampleRate: amrWBSampleRate, Channels: 1}, nil
+}
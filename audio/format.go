@@ -0,0 +1,91 @@
+// Package audio converts between the uncompressed audio formats of the Speech service in pure Go, so that one
+// synthesized clip can be delivered in several formats without another request, evens out the silence and
+// loudness of clips, see Process, and measures their duration, see Probe. Formats are named like AudioOutput
+// values, such as "riff-24khz-16bit-mono-pcm" or "raw-8khz-8bit-mono-mulaw".
+package audio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encoding is the sample encoding of a Format.
+type Encoding string
+
+const (
+	EncodingPCM   Encoding = "pcm"   // 16 bit signed little-endian linear PCM
+	EncodingALaw  Encoding = "alaw"  // 8 bit G.711 A-law
+	EncodingMuLaw Encoding = "mulaw" // 8 bit G.711 μ-law
+)
+
+// Format is an uncompressed mono audio format.
+type Format struct {
+	WAV        bool // samples are wrapped in a RIFF (WAV) header
+	SampleRate int
+	Encoding   Encoding
+}
+
+// ParseFormat returns the format named name, an uncompressed AudioOutput such as "raw-24khz-16bit-mono-pcm".
+func ParseFormat(name string) (Format, error) {
+	// formats are named <container>-<rate>-<bits>-mono-<encoding>
+	parts := strings.Split(name, "-")
+	if len(parts) != 5 || (parts[0] != "raw" && parts[0] != "riff") || parts[3] != "mono" {
+		return Format{}, fmt.Errorf("format %q is not uncompressed audio", name)
+	}
+	f := Format{WAV: parts[0] == "riff", Encoding: Encoding(parts[4])}
+	switch f.Encoding {
+	case EncodingPCM, EncodingALaw, EncodingMuLaw:
+	default:
+		return Format{}, fmt.Errorf("format %q is not uncompressed audio", name)
+	}
+	if parts[2] != fmt.Sprintf("%dbit", f.BitsPerSample()) {
+		return Format{}, fmt.Errorf("format %q has an unsupported sample size", name)
+	}
+
+	rate := parts[1]
+	multiplier := 1
+	if strings.HasSuffix(rate, "khz") {
+		rate, multiplier = strings.TrimSuffix(rate, "khz"), 1000
+	} else {
+		rate = strings.TrimSuffix(rate, "hz")
+	}
+	n, err := strconv.Atoi(rate)
+	if err != nil || n <= 0 {
+		return Format{}, fmt.Errorf("format %q has an invalid sample rate", name)
+	}
+	f.SampleRate = n * multiplier
+	return f, nil
+}
+
+// String returns the AudioOutput name of f.
+func (f Format) String() string {
+	container := "raw"
+	if f.WAV {
+		container = "riff"
+	}
+	rate := fmt.Sprintf("%dhz", f.SampleRate)
+	if f.SampleRate%1000 == 0 {
+		rate = fmt.Sprintf("%dkhz", f.SampleRate/1000)
+	}
+	return fmt.Sprintf("%s-%s-%dbit-mono-%s", container, rate, f.BitsPerSample(), f.Encoding)
+}
+
+// BitsPerSample is the size of a sample of f.
+func (f Format) BitsPerSample() int {
+	if f.Encoding == EncodingPCM {
+		return 16
+	}
+	return 8
+}
+
+// BytesPerSecond is the data rate of f.
+func (f Format) BytesPerSecond() int {
+	return f.SampleRate * f.BitsPerSample() / 8
+}
+
+// Duration returns the playing time of n bytes of samples in f.
+func (f Format) Duration(n int) time.Duration {
+	return time.Duration(int64(n) * int64(time.Second) / int64(f.BytesPerSecond()))
+}
@@ -0,0 +1,122 @@
+package audio
+
+import "encoding/binary"
+
+// Decode returns the linear samples of data in encoding e. A trailing odd byte of PCM data is ignored.
+func Decode(data []byte, e Encoding) []int16 {
+	switch e {
+	case EncodingALaw:
+		samples := make([]int16, len(data))
+		for i, b := range data {
+			samples[i] = aLawToLinear(b)
+		}
+		return samples
+	case EncodingMuLaw:
+		samples := make([]int16, len(data))
+		for i, b := range data {
+			samples[i] = muLawToLinear(b)
+		}
+		return samples
+	}
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+	return samples
+}
+
+// Encode returns linear samples in encoding e.
+func Encode(samples []int16, e Encoding) []byte {
+	switch e {
+	case EncodingALaw:
+		data := make([]byte, len(samples))
+		for i, s := range samples {
+			data[i] = linearToALaw(s)
+		}
+		return data
+	case EncodingMuLaw:
+		data := make([]byte, len(samples))
+		for i, s := range samples {
+			data[i] = linearToMuLaw(s)
+		}
+		return data
+	}
+	data := make([]byte, 2*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(s))
+	}
+	return data
+}
+
+// The G.711 companding follows the reference implementation of the ITU-T, see
+// https://www.itu.int/rec/T-REC-G.711.
+
+// aLawSegmentEnds are the largest 13 bit magnitudes of the A-law segments.
+var aLawSegmentEnds = [8]int{0x1F, 0x3F, 0x7F, 0xFF, 0x1FF, 0x3FF, 0x7FF, 0xFFF}
+
+func linearToALaw(s int16) byte {
+	v := int(s) >> 3
+	mask := byte(0xD5)
+	if v < 0 {
+		mask = 0x55
+		v = -v - 1
+	}
+	segment := 0
+	for segment < len(aLawSegmentEnds) && v > aLawSegmentEnds[segment] {
+		segment++
+	}
+	if segment == len(aLawSegmentEnds) {
+		return 0x7F ^ mask
+	}
+	a := byte(segment << 4)
+	if segment < 2 {
+		a |= byte(v>>1) & 0x0F
+	} else {
+		a |= byte(v>>segment) & 0x0F
+	}
+	return a ^ mask
+}
+
+func aLawToLinear(a byte) int16 {
+	a ^= 0x55
+	v := int(a&0x0F)<<4 + 8
+	segment := int(a&0x70) >> 4
+	if segment > 0 {
+		v = (v + 0x100) << (segment - 1)
+	}
+	if a&0x80 == 0 {
+		v = -v
+	}
+	return int16(v)
+}
+
+const (
+	muLawBias = 0x84
+	muLawClip = 32635
+)
+
+func linearToMuLaw(s int16) byte {
+	v := int(s)
+	var sign byte
+	if v < 0 {
+		v = -v
+		sign = 0x80
+	}
+	v = min(v, muLawClip) + muLawBias
+	exponent := 7
+	for mask := 0x4000; v&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := byte(v>>(exponent+3)) & 0x0F
+	return ^(sign | byte(exponent<<4) | mantissa)
+}
+
+func muLawToLinear(u byte) int16 {
+	u = ^u
+	exponent := int(u>>4) & 0x07
+	v := (int(u&0x0F)<<3+muLawBias)<<exponent - muLawBias
+	if u&0x80 != 0 {
+		v = -v
+	}
+	return int16(v)
+}
@@ -0,0 +1,37 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestG711(t *testing.T) {
+	// silence and full scale of the reference implementation.
+	assert.Equal(t, []byte{0xD5, 0xAA, 0x2A}, Encode([]int16{0, math.MaxInt16, math.MinInt16}, EncodingALaw))
+	assert.Equal(t, []byte{0xFF, 0x80, 0x00}, Encode([]int16{0, math.MaxInt16, math.MinInt16}, EncodingMuLaw))
+
+	for _, e := range []Encoding{EncodingALaw, EncodingMuLaw} {
+		// every code decodes to a value which encodes to the same code, except for μ-law's negative zero.
+		for code := 0; code < 256; code++ {
+			if e == EncodingMuLaw && code == 0x7F {
+				continue
+			}
+			linear := Decode([]byte{byte(code)}, e)
+			assert.Equal(t, []byte{byte(code)}, Encode(linear, e), "%s %#x", e, code)
+		}
+		// the quantization error grows with the magnitude, staying below 1/16 of it.
+		for _, s := range []int16{1000, -1000, 12345, -12345, 30000, -30000} {
+			decoded := Decode(Encode([]int16{s}, e), e)[0]
+			assert.InDelta(t, s, decoded, math.Abs(float64(s))/16, "%s %d", e, s)
+		}
+	}
+}
+
+func TestPCM(t *testing.T) {
+	samples := []int16{0, 1, -1, math.MaxInt16, math.MinInt16}
+	data := Encode(samples, EncodingPCM)
+	assert.Equal(t, []byte{0, 0, 1, 0, 0xFF, 0xFF, 0xFF, 0x7F, 0, 0x80}, data)
+	assert.Equal(t, samples, Decode(data, EncodingPCM))
+}
@@ -0,0 +1,129 @@
+package audio
+
+import "math"
+
+// Gating of the loudness measurement, see ITU-R BS.1770-4.
+const (
+	loudnessBlock        = 0.4 // seconds
+	loudnessStep         = 0.1 // seconds, blocks overlap by 75%
+	loudnessAbsoluteGate = -70 // LUFS
+	loudnessRelativeGate = -10 // LU below the loudness of the blocks above the absolute gate
+)
+
+// Level returns the RMS level of samples in dBFS, where a full scale square wave is 0 dBFS. Silence is
+// negative infinity.
+func Level(samples []int16) float64 {
+	var sum float64
+	for _, s := range samples {
+		v := float64(s) / 32768
+		sum += v * v
+	}
+	if len(samples) == 0 {
+		return math.Inf(-1)
+	}
+	return 10 * math.Log10(sum/float64(len(samples)))
+}
+
+// Loudness returns the integrated loudness of samples at rate in LUFS, measured as specified by ITU-R BS.1770
+// and EBU R 128: K-weighted and gated in overlapping blocks of 400 ms. A full scale sine of 1 kHz is -3 LUFS.
+// Silence, and audio below the absolute gate of -70 LUFS, is negative infinity.
+func Loudness(samples []int16, rate int) float64 {
+	weighted := kWeighting(samples, rate)
+
+	block := int(loudnessBlock * float64(rate))
+	step := int(loudnessStep * float64(rate))
+	var powers []float64
+	if len(weighted) < block {
+		// too short for gating, measure the clip as a single block.
+		powers = append(powers, meanSquare(weighted))
+	}
+	for start := 0; start+block <= len(weighted); start += step {
+		powers = append(powers, meanSquare(weighted[start:start+block]))
+	}
+
+	gated := gate(powers, powerOf(loudnessAbsoluteGate))
+	if len(gated) == 0 {
+		return math.Inf(-1)
+	}
+	relative := loudnessOf(mean(gated)) + loudnessRelativeGate
+	gated = gate(gated, powerOf(relative))
+	if len(gated) == 0 {
+		return math.Inf(-1)
+	}
+	return loudnessOf(mean(gated))
+}
+
+func loudnessOf(power float64) float64 {
+	return -0.691 + 10*math.Log10(power)
+}
+
+func powerOf(loudness float64) float64 {
+	return math.Pow(10, (loudness+0.691)/10)
+}
+
+// gate returns the powers above threshold.
+func gate(powers []float64, threshold float64) []float64 {
+	var above []float64
+	for _, p := range powers {
+		if p > threshold {
+			above = append(above, p)
+		}
+	}
+	return above
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func meanSquare(samples []float64) float64 {
+	var sum float64
+	for _, v := range samples {
+		sum += v * v
+	}
+	return sum / float64(max(1, len(samples)))
+}
+
+// kWeighting returns samples, scaled to [-1, 1], through the K-weighting filter of BS.1770: a high shelf
+// modeling the head followed by a high-pass. The coefficients are derived for rate with the bilinear transform,
+// matching the 48 kHz coefficients of the standard.
+func kWeighting(samples []int16, rate int) []float64 {
+	out := make([]float64, len(samples))
+	for i, s := range samples {
+		out[i] = float64(s) / 32768
+	}
+
+	// high shelf of about +4 dB above 1.5 kHz
+	k := math.Tan(math.Pi * 1681.974450955533 / float64(rate))
+	q := 0.7071752369554196
+	vh := math.Pow(10, 3.999843853973347/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	biquad(out,
+		[3]float64{(vh + vb*k/q + k*k) / a0, 2 * (k*k - vh) / a0, (vh - vb*k/q + k*k) / a0},
+		[2]float64{2 * (k*k - 1) / a0, (1 - k/q + k*k) / a0})
+
+	// high-pass at 38 Hz
+	k = math.Tan(math.Pi * 38.13547087602444 / float64(rate))
+	q = 0.5003270373238773
+	a0 = 1 + k/q + k*k
+	biquad(out,
+		[3]float64{1, -2, 1},
+		[2]float64{2 * (k*k - 1) / a0, (1 - k/q + k*k) / a0})
+	return out
+}
+
+// biquad filters x in place with numerator b and denominator 1, a[0], a[1].
+func biquad(x []float64, b [3]float64, a [2]float64) {
+	var x1, x2, y1, y2 float64
+	for i, x0 := range x {
+		y0 := b[0]*x0 + b[1]*x1 + b[2]*x2 - a[0]*y1 - a[1]*y2
+		x2, x1 = x1, x0
+		y2, y1 = y1, y0
+		x[i] = y0
+	}
+}
@@ -0,0 +1,35 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevel(t *testing.T) {
+	// a sine is 3 dB below the square wave of the same peak.
+	assert.InDelta(t, -3.01+20*math.Log10(10000.0/32768), Level(sine(1000, 24000, 1)), 0.01)
+	assert.True(t, math.IsInf(Level(make([]int16, 100)), -1))
+}
+
+func TestLoudness(t *testing.T) {
+	// the reference signal of BS.1770, a full scale sine of 997 Hz, measures -3.01 LUFS at every rate.
+	for _, rate := range []int{8000, 16000, 24000, 44100, 48000} {
+		full := Gain(sine(997, rate, 2), 20*math.Log10(32767.0/10000))
+		assert.InDelta(t, -3.01, Loudness(full, rate), 0.1, "%d Hz", rate)
+	}
+
+	// K-weighting attenuates low frequencies and boosts high ones.
+	assert.Less(t, Loudness(sine(50, 48000, 2), 48000), Loudness(sine(1000, 48000, 2), 48000)-1)
+	assert.Greater(t, Loudness(sine(4000, 48000, 2), 48000), Loudness(sine(1000, 48000, 2), 48000)+2)
+
+	// silence between speech is gated out, only the blocks overlapping its edges lower the loudness. Measured
+	// without gating, the pause would lower it by 1.8 LU.
+	tone := sine(1000, 24000, 1)
+	gapped := append(append(append([]int16{}, tone...), make([]int16, 24000)...), tone...)
+	assert.InDelta(t, Loudness(tone, 24000), Loudness(gapped, 24000), 1)
+
+	assert.True(t, math.IsInf(Loudness(make([]int16, 24000), 24000), -1))
+	assert.InDelta(t, Loudness(tone, 24000), Loudness(tone[:2400], 24000), 0.5)
+}
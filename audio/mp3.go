@@ -0,0 +1,151 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// MPEG versions, as encoded in the frame header.
+const (
+	mpeg25 = 0
+	mpeg2  = 2
+	mpeg1  = 3
+)
+
+// Bitrates of Layer III in kbit/s by bitrate index, for MPEG-1 and for MPEG-2 and 2.5.
+var (
+	mp3Bitrates1 = [16]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0}
+	mp3Bitrates2 = [16]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0}
+)
+
+// mp3SampleRates are the sample rates by version and sample rate index.
+var mp3SampleRates = map[int][3]int{
+	mpeg1:  {44100, 48000, 32000},
+	mpeg2:  {22050, 24000, 16000},
+	mpeg25: {11025, 12000, 8000},
+}
+
+// mp3Frame is a parsed MPEG audio Layer III frame header.
+type mp3Frame struct {
+	version    int
+	sampleRate int
+	channels   int
+	crc        bool // a 16 bit CRC follows the header
+	size       int  // in bytes, including the header
+}
+
+// samples is the number of samples per channel in the frame.
+func (f mp3Frame) samples() int {
+	if f.version == mpeg1 {
+		return 1152
+	}
+	return 576
+}
+
+// sideInfoSize is the size of the side information following the header and CRC, where a Xing header starts.
+func (f mp3Frame) sideInfoSize() int {
+	switch {
+	case f.version == mpeg1 && f.channels == 1:
+		return 17
+	case f.version == mpeg1:
+		return 32
+	case f.channels == 1:
+		return 9
+	}
+	return 17
+}
+
+// parseMP3Frame parses the Layer III frame header at the start of b.
+func parseMP3Frame(b []byte) (mp3Frame, bool) {
+	if len(b) < 4 {
+		return mp3Frame{}, false
+	}
+	h := binary.BigEndian.Uint32(b)
+	version := int(h>>19) & 3
+	layer := (h >> 17) & 3
+	bitrateIndex := (h >> 12) & 15
+	rateIndex := (h >> 10) & 3
+	if h>>21 != 0x7FF || version == 1 || layer != 1 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return mp3Frame{}, false
+	}
+	f := mp3Frame{version: version, sampleRate: mp3SampleRates[version][rateIndex], channels: 2, crc: (h>>16)&1 == 0}
+	if (h>>6)&3 == 3 {
+		f.channels = 1
+	}
+	padding := int(h>>9) & 1
+	if version == mpeg1 {
+		f.size = 144*mp3Bitrates1[bitrateIndex]*1000/f.sampleRate + padding
+	} else {
+		f.size = 72*mp3Bitrates2[bitrateIndex]*1000/f.sampleRate + padding
+	}
+	return f, true
+}
+
+// probeMP3 returns the duration of an MP3 file. The frame count of a Xing (or Info) or VBRI header in the first
+// frame is used when present, otherwise the frames are counted.
+func probeMP3(data []byte) (Info, error) {
+	// skip an ID3v2 tag, whose size is stored in 7 bits per byte.
+	if len(data) >= 10 && string(data[0:3]) == "ID3" {
+		size := 10 + (int(data[6])<<21 | int(data[7])<<14 | int(data[8])<<7 | int(data[9]))
+		if data[5]&0x10 != 0 {
+			size += 10 // footer
+		}
+		data = data[min(size, len(data)):]
+	}
+
+	offset := 0
+	for offset < len(data) {
+		if _, ok := parseMP3Frame(data[offset:]); ok {
+			break
+		}
+		offset++
+	}
+	first, ok := parseMP3Frame(data[offset:])
+	if !ok {
+		return Info{}, fmt.Errorf("audio has no MP3 frame")
+	}
+	info := Info{SampleRate: first.sampleRate, Channels: first.channels}
+	duration := func(frames int) time.Duration {
+		return time.Duration(int64(frames) * int64(first.samples()) * int64(time.Second) / int64(first.sampleRate))
+	}
+
+	frame := data[offset:min(offset+first.size, len(data))]
+	xing := 4 + first.sideInfoSize()
+	if first.crc {
+		xing += 2
+	}
+	if len(frame) >= xing+8 {
+		if tag := string(frame[xing : xing+4]); tag == "Xing" || tag == "Info" {
+			if binary.BigEndian.Uint32(frame[xing+4:])&1 != 0 && len(frame) >= xing+12 {
+				info.Duration = duration(int(binary.BigEndian.Uint32(frame[xing+8:])))
+				return info, nil
+			}
+			// the frame holding the header is silent, it is not counted as audio.
+			offset += first.size
+		}
+	}
+	// the VBRI header of the Fraunhofer encoder is at a fixed offset.
+	if vbri := 4 + 32; len(frame) >= vbri+18 && string(frame[vbri:vbri+4]) == "VBRI" {
+		info.Duration = duration(int(binary.BigEndian.Uint32(frame[vbri+14:])))
+		return info, nil
+	}
+
+	frames := 0
+	for offset < len(data) {
+		f, ok := parseMP3Frame(data[offset:])
+		if !ok {
+			// resynchronize after garbage, such as a trailing ID3v1 tag.
+			offset++
+			continue
+		}
+		if offset+f.size > len(data) {
+			// a truncated last frame.
+			break
+		}
+		frames++
+		offset += f.size
+	}
+	info.Duration = duration(frames)
+	return info, nil
+}
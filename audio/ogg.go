@@ -0,0 +1,80 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// oggCapture starts every Ogg page.
+const oggCapture = "OggS"
+
+// opusRate is the rate of Opus granule positions, whatever the sample rate of the input.
+const opusRate = 48000
+
+// opusHead is the identification header of an Opus stream, see RFC 7845.
+type opusHead struct {
+	channels   int
+	preSkip    int // samples at 48 kHz to discard at the start
+	sampleRate int // of the input, informational
+}
+
+func parseOpusHead(b []byte) (opusHead, bool) {
+	if len(b) < 19 || string(b[0:8]) != "OpusHead" {
+		return opusHead{}, false
+	}
+	return opusHead{
+		channels:   int(b[9]),
+		preSkip:    int(binary.LittleEndian.Uint16(b[10:12])),
+		sampleRate: int(binary.LittleEndian.Uint32(b[12:16])),
+	}, true
+}
+
+// probeOgg returns the duration of an Ogg Opus file from the granule position of its last page, which is the
+// number of samples at 48 kHz decoded by the end of the page.
+func probeOgg(data []byte) (Info, error) {
+	var (
+		head    opusHead
+		granule int64 = -1
+	)
+	for offset := 0; offset < len(data); {
+		page := data[offset:]
+		if len(page) < 27 || string(page[0:4]) != oggCapture {
+			return Info{}, fmt.Errorf("audio has an invalid Ogg page at offset %d", offset)
+		}
+		segments := int(page[26])
+		if len(page) < 27+segments {
+			break
+		}
+		size := 27 + segments
+		for _, s := range page[27 : 27+segments] {
+			size += int(s)
+		}
+		if offset == 0 {
+			var ok bool
+			if head, ok = parseOpusHead(page[27+segments : min(size, len(page))]); !ok {
+				return Info{}, fmt.Errorf("audio is not an Ogg Opus stream")
+			}
+		}
+		if size > len(page) {
+			// a truncated last page, its granule position has not been reached.
+			break
+		}
+		// pages on which no packet ends have a granule position of -1.
+		if g := int64(binary.LittleEndian.Uint64(page[6:14])); g >= 0 {
+			granule = g
+		}
+		offset += size
+	}
+	if granule < 0 {
+		return Info{}, fmt.Errorf("audio has no Ogg page with a granule position")
+	}
+	info := Info{SampleRate: head.sampleRate, Channels: head.channels}
+	if info.SampleRate == 0 {
+		info.SampleRate = opusRate
+	}
+	if samples := granule - int64(head.preSkip); samples > 0 {
+		info.Duration = time.Duration(samples * int64(time.Second) / opusRate)
+	}
+	return info, nil
+}
@@ -0,0 +1,75 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported is wrapped by the errors of Probe for formats it cannot measure.
+var ErrUnsupported = errors.New("unsupported audio format")
+
+// Info describes a clip, see Probe.
+type Info struct {
+	Duration   time.Duration
+	SampleRate int // of the audio before encoding, which Opus resamples to 48 kHz
+	Channels   int
+	Bitrate    int // average bits per second of the data
+}
+
+// Probe returns the duration and sample layout of data, a clip in the format named format such as
+// "audio-24khz-48kbitrate-mono-mp3". Uncompressed audio is measured from its size, MP3 from its Xing or VBRI
+// header or else its frame headers, Ogg from the granule position of its last page, WebM from its Duration
+// element or else its last block and AMR-WB from its frame count.
+//
+// TrueSilk, a proprietary codec without a documented frame layout, is not supported; the error for it, as for
+// any unknown format, wraps ErrUnsupported.
+func Probe(data []byte, format string) (Info, error) {
+	var (
+		info Info
+		err  error
+	)
+	switch {
+	case strings.HasSuffix(format, "-pcm") || strings.HasSuffix(format, "-alaw") || strings.HasSuffix(format, "-mulaw"):
+		info, err = probePCM(data, format)
+	case strings.HasPrefix(format, "amr-wb-"):
+		info, err = probeAMRWB(data)
+	case strings.HasSuffix(format, "-mp3"):
+		info, err = probeMP3(data)
+	case strings.HasSuffix(format, "-opus"):
+		// "audio-*-opus" formats carry no container name, so look at the data.
+		switch {
+		case bytes.HasPrefix(data, []byte(oggCapture)):
+			info, err = probeOgg(data)
+		case bytes.HasPrefix(data, ebmlMagic):
+			info, err = probeWebM(data)
+		default:
+			err = fmt.Errorf("audio is neither Ogg nor WebM")
+		}
+	default:
+		return Info{}, fmt.Errorf("%w, cannot probe %q", ErrUnsupported, format)
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	if info.Duration > 0 {
+		info.Bitrate = int(int64(len(data)) * 8 * int64(time.Second) / int64(info.Duration))
+	}
+	return info, nil
+}
+
+func probePCM(data []byte, format string) (Info, error) {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return Info{}, err
+	}
+	samples := data
+	if f.WAV {
+		if f, samples, err = ParseWAV(data); err != nil {
+			return Info{}, err
+		}
+	}
+	return Info{Duration: f.Duration(len(samples)), SampleRate: f.SampleRate, Channels: 1}, nil
+}
@@ -0,0 +1,202 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbePCM(t *testing.T) {
+	f := Format{WAV: true, SampleRate: 24000, Encoding: EncodingPCM}
+	info, err := Probe(EncodeWAV(f, make([]byte, 24000)), "riff-24khz-16bit-mono-pcm")
+	if assert.NoError(t, err) {
+		assert.Equal(t, Info{Duration: 500 * time.Millisecond, SampleRate: 24000, Channels: 1, Bitrate: 384704}, info)
+	}
+	info, err = Probe(make([]byte, 8000), "raw-8khz-8bit-mono-alaw")
+	if assert.NoError(t, err) {
+		assert.Equal(t, Info{Duration: time.Second, SampleRate: 8000, Channels: 1, Bitrate: 64000}, info)
+	}
+
+	_, err = Probe(make([]byte, 100), "raw-16khz-16bit-mono-truesilk")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestProbeAMRWB(t *testing.T) {
+	// 40 frames of 23.85 kbit/s speech (type 8) and 10 comfort noise frames (type 9) are one second.
+	speech := make([]byte, 61)
+	speech[0] = 8<<3 | 0x04
+	sid := make([]byte, 6)
+	sid[0] = 9<<3 | 0x04
+	data := append([]byte("#!AMR-WB\n"), append(bytes.Repeat(speech, 40), bytes.Repeat(sid, 10)...)...)
+	info, err := Probe(data, "amr-wb-16000hz")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Second, info.Duration)
+		assert.Equal(t, 16000, info.SampleRate)
+		assert.Equal(t, 1, info.Channels)
+	}
+
+	// the header is optional and a truncated last frame is ignored.
+	info, err = Probe(append(bytes.Repeat(speech, 5), speech[:10]...), "amr-wb-16000hz")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 100*time.Millisecond, info.Duration)
+	}
+
+	_, err = Probe([]byte{10 << 3}, "amr-wb-16000hz")
+	assert.Error(t, err, "frame type 10 is reserved")
+}
+
+// mp3Frames returns n MPEG-1 Layer III frames of 128 kbit/s at 44.1 kHz, mono, each 417 bytes.
+func mp3Frames(n int) []byte {
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0xC0})
+	return bytes.Repeat(frame, n)
+}
+
+func TestProbeMP3(t *testing.T) {
+	// frames are counted, skipping an ID3v2 tag at the start and an ID3v1 tag at the end.
+	id3 := append([]byte{'I', 'D', '3', 4, 0, 0, 0, 0, 1, 0}, make([]byte, 128)...)
+	data := append(append(id3, mp3Frames(100)...), append([]byte("TAG"), make([]byte, 125)...)...)
+	info, err := Probe(data, "audio-48khz-192kbitrate-mono-mp3")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Duration(100*1152)*time.Second/44100, info.Duration)
+		assert.Equal(t, 44100, info.SampleRate)
+		assert.Equal(t, 1, info.Channels)
+	}
+
+	// a Xing header after the side information gives the frame count of VBR files.
+	data = mp3Frames(10)
+	copy(data[4+17:], "Xing\x00\x00\x00\x01\x00\x00\x03\xE8")
+	info, err = Probe(data, "audio-48khz-192kbitrate-mono-mp3")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Duration(1000*1152)*time.Second/44100, info.Duration)
+	}
+
+	// the Xing header follows the CRC of protected frames.
+	data = mp3Frames(10)
+	for i := 0; i < len(data); i += 417 {
+		data[i+1] = 0xFA
+	}
+	copy(data[4+2+17:], "Xing\x00\x00\x00\x01\x00\x00\x03\xE8")
+	info, err = Probe(data, "audio-48khz-192kbitrate-mono-mp3")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Duration(1000*1152)*time.Second/44100, info.Duration)
+	}
+
+	// without a frame count, the frame holding an Info header is not counted.
+	data = mp3Frames(10)
+	copy(data[4+17:], "Info\x00\x00\x00\x00")
+	info, err = Probe(data, "audio-48khz-192kbitrate-mono-mp3")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Duration(9*1152)*time.Second/44100, info.Duration)
+	}
+
+	// MPEG-2 frames of 48 kbit/s at 24 kHz with a VBRI header.
+	frame := make([]byte, 144)
+	copy(frame, []byte{0xFF, 0xF3, 0x64, 0xC0})
+	copy(frame[36:], "VBRI")
+	binary.BigEndian.PutUint32(frame[36+14:], 250)
+	info, err = Probe(frame, "audio-24khz-48kbitrate-mono-mp3")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 6*time.Second, info.Duration)
+		assert.Equal(t, 24000, info.SampleRate)
+	}
+
+	_, err = Probe([]byte("mp3"), "audio-24khz-48kbitrate-mono-mp3")
+	assert.Error(t, err)
+}
+
+// oggPage returns an Ogg page carrying a single packet.
+func oggPage(granule int64, packet []byte) []byte {
+	page := []byte(oggCapture)
+	page = append(page, 0, 0)
+	page = binary.LittleEndian.AppendUint64(page, uint64(granule))
+	page = append(page, make([]byte, 12)...) // serial number, sequence number and checksum
+	var segments []byte
+	for n := len(packet); ; n -= 255 {
+		segments = append(segments, byte(min(n, 255)))
+		if n < 255 {
+			break
+		}
+	}
+	page = append(page, byte(len(segments)))
+	page = append(page, segments...)
+	return append(page, packet...)
+}
+
+func TestProbeOgg(t *testing.T) {
+	head := []byte("OpusHead\x01\x01")
+	head = binary.LittleEndian.AppendUint16(head, 312)
+	head = binary.LittleEndian.AppendUint32(head, 24000)
+	head = append(head, 0, 0, 0)
+	data := append(oggPage(0, head), oggPage(0, []byte("OpusTags"))...)
+	data = append(data, oggPage(48000+312, make([]byte, 300))...)
+	data = append(data, oggPage(-1, make([]byte, 100))...)
+	data = append(data, oggPage(2*48000+312, make([]byte, 300))...)
+
+	info, err := Probe(data, "ogg-24khz-16bit-mono-opus")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2*time.Second, info.Duration)
+		assert.Equal(t, 24000, info.SampleRate)
+		assert.Equal(t, 1, info.Channels)
+	}
+	// a truncated page of a stream is ignored.
+	info, err = Probe(data[:len(data)-10], "audio-24khz-16bit-48kbps-mono-opus")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Second, info.Duration)
+	}
+}
+
+// ebml returns an element with the given ID and body, size is unknown when body is nil.
+func ebml(id uint32, body []byte) []byte {
+	var b []byte
+	for shift := 24; shift >= 0; shift -= 8 {
+		if c := byte(id >> shift); c != 0 || len(b) > 0 {
+			b = append(b, c)
+		}
+	}
+	if body == nil {
+		return append(b, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF)
+	}
+	// sizes are written in 8 bytes, the first holding the length marker.
+	b = append(b, 0x01)
+	b = append(b, binary.BigEndian.AppendUint64(nil, uint64(len(body)))[1:]...)
+	return append(b, body...)
+}
+
+func TestProbeWebM(t *testing.T) {
+	header := ebml(0x1A45DFA3, []byte{0x42, 0x82, 0x84, 'w', 'e', 'b', 'm'})
+	tracks := ebml(ebmlTracks, ebml(ebmlTrackEntry, append(
+		ebml(ebmlCodecDelay, []byte{0x63, 0x2E, 0xA0}), // 6.5 ms
+		ebml(ebmlAudio, append(
+			ebml(ebmlSamplingFrequency, binary.BigEndian.AppendUint64(nil, math.Float64bits(48000))),
+			ebml(ebmlChannels, []byte{1})...))...)))
+
+	// a file with a duration, 1.5 s
+	info := ebml(ebmlInfo, append(
+		ebml(ebmlTimecodeScale, []byte{0x0F, 0x42, 0x40}),
+		ebml(ebmlDuration, binary.BigEndian.AppendUint64(nil, math.Float64bits(1500)))...))
+	data := append(header, ebml(ebmlSegment, append(info, tracks...))...)
+	probed, err := Probe(data, "webm-24khz-16bit-mono-opus")
+	if assert.NoError(t, err) {
+		assert.Equal(t, Info{Duration: 1500 * time.Millisecond, SampleRate: 48000, Channels: 1, Bitrate: len(data) * 8 * 2 / 3}, probed)
+	}
+
+	// a stream of unknown size, 50 blocks of 20 ms
+	data = append(header, ebml(ebmlSegment, nil)...)
+	data = append(data, tracks...)
+	data = append(data, ebml(ebmlCluster, nil)...)
+	data = append(data, ebml(ebmlTimecode, []byte{0})...)
+	for i := 0; i < 50; i++ {
+		// track 1, the relative timecode, keyframe and a CELT packet of 20 ms
+		block := binary.BigEndian.AppendUint16([]byte{0x81}, uint16(20*i))
+		data = append(data, ebml(ebmlSimpleBlock, append(block, 0x80, 0xF8, 1, 2, 3))...)
+	}
+	probed, err = Probe(data, "webm-24khz-16bit-mono-opus")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Second-6500*time.Microsecond, probed.Duration)
+	}
+}
@@ -0,0 +1,105 @@
+package audio
+
+import (
+	"math"
+	"time"
+)
+
+// trimWindow is the length of the windows whose level decides whether audio is silent.
+const trimWindow = 10 * time.Millisecond
+
+// Processing configures Process. Its steps run in the order of the fields, a zero field skips its step.
+type Processing struct {
+	// TrimThreshold is the level in dBFS, such as -50, below which leading and trailing audio is trimmed as
+	// silence.
+	TrimThreshold float64
+	// TargetLoudness is the integrated loudness in LUFS, such as -16, to normalize to, see Loudness.
+	TargetLoudness float64
+	// TargetLevel is the RMS level in dBFS to normalize to, see Level. It is ignored when TargetLoudness is set.
+	TargetLevel float64
+	// PadStart and PadEnd are the lengths of silence added before and after the audio.
+	PadStart, PadEnd time.Duration
+}
+
+// Process applies p to data, audio in the format named format, see ParseFormat. Clips processed alike
+// concatenate without jumps in loudness or uneven pauses.
+func Process(data []byte, format string, p Processing) ([]byte, error) {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	samples := data
+	if f.WAV {
+		if f, samples, err = ParseWAV(data); err != nil {
+			return nil, err
+		}
+	}
+
+	linear := Decode(samples, f.Encoding)
+	if p.TrimThreshold != 0 {
+		linear = Trim(linear, f.SampleRate, p.TrimThreshold)
+	}
+	switch {
+	case p.TargetLoudness != 0:
+		linear = Gain(linear, p.TargetLoudness-Loudness(linear, f.SampleRate))
+	case p.TargetLevel != 0:
+		linear = Gain(linear, p.TargetLevel-Level(linear))
+	}
+	linear = Pad(linear, f.SampleRate, p.PadStart, p.PadEnd)
+
+	samples = Encode(linear, f.Encoding)
+	if f.WAV {
+		return EncodeWAV(f, samples), nil
+	}
+	return samples, nil
+}
+
+// Trim removes the leading and trailing audio whose level is below threshold dBFS, measured in windows of 10
+// ms. Audio which is silent throughout is trimmed to nothing.
+func Trim(samples []int16, rate int, threshold float64) []int16 {
+	window := max(1, int(int64(rate)*int64(trimWindow)/int64(time.Second)))
+	loud := func(start int) bool {
+		return Level(samples[start:min(start+window, len(samples))]) >= threshold
+	}
+	start := 0
+	for start < len(samples) && !loud(start) {
+		start += window
+	}
+	if start >= len(samples) {
+		return []int16{}
+	}
+	end := len(samples)
+	for last := (len(samples) - 1) / window * window; last > start && !loud(last); last -= window {
+		end = last
+	}
+	return samples[start:end]
+}
+
+// Pad returns samples with before and after worth of silence added at either end.
+func Pad(samples []int16, rate int, before, after time.Duration) []int16 {
+	n := func(d time.Duration) int {
+		return max(0, int(int64(rate)*int64(d)/int64(time.Second)))
+	}
+	padded := make([]int16, n(before)+len(samples)+n(after))
+	copy(padded[n(before):], samples)
+	return padded
+}
+
+// Gain returns samples amplified by db decibels. The gain is limited so that the loudest sample does not clip;
+// silence is returned unchanged.
+func Gain(samples []int16, db float64) []int16 {
+	peak := 0.0
+	for _, s := range samples {
+		peak = max(peak, math.Abs(float64(s)))
+	}
+	out := make([]int16, len(samples))
+	if peak == 0 || math.IsInf(db, 0) || math.IsNaN(db) {
+		copy(out, samples)
+		return out
+	}
+	gain := min(math.Pow(10, db/20), math.MaxInt16/peak)
+	for i, s := range samples {
+		out[i] = clip16(float64(s) * gain)
+	}
+	return out
+}
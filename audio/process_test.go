@@ -0,0 +1,83 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// noise returns n samples of low-level noise, -70 dBFS.
+func noise(n int) []int16 {
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = int16((i*7919)%21 - 10)
+	}
+	return samples
+}
+
+func TestTrim(t *testing.T) {
+	tone := sine(1000, 24000, 0.5)
+	clip := append(append(noise(7200), tone...), noise(4800)...)
+	trimmed := Trim(clip, 24000, -50)
+	// the tone starts and ends on a boundary of the 10 ms windows.
+	assert.Equal(t, tone, trimmed)
+	// otherwise the boundaries are found to within a window.
+	assert.InDelta(t, len(tone), len(Trim(clip[100:], 24000, -50)), 240)
+
+	assert.Empty(t, Trim(noise(24000), 24000, -50))
+	assert.Len(t, Trim(tone, 24000, -50), len(tone))
+}
+
+func TestPadGain(t *testing.T) {
+	tone := sine(1000, 24000, 0.5)
+	padded := Pad(tone, 24000, 100*time.Millisecond, 250*time.Millisecond)
+	assert.Len(t, padded, 2400+len(tone)+6000)
+	assert.Equal(t, make([]int16, 2400), padded[:2400])
+	assert.Equal(t, tone, padded[2400:2400+len(tone)])
+
+	assert.InDelta(t, Level(tone)-6, Level(Gain(tone, -6)), 0.01)
+	// the gain is limited at full scale.
+	loud := Gain(tone, 20)
+	assert.InDelta(t, 0, 20*math.Log10(32767/peak(loud)), 0.01)
+}
+
+func peak(samples []int16) float64 {
+	p := 0.0
+	for _, s := range samples {
+		p = max(p, math.Abs(float64(s)))
+	}
+	return p
+}
+
+func TestProcess(t *testing.T) {
+	f := Format{WAV: true, SampleRate: 24000, Encoding: EncodingPCM}
+	quiet := Gain(sine(440, 24000, 1), -20)
+	wav := EncodeWAV(f, Encode(append(append(noise(4800), quiet...), noise(9600)...), EncodingPCM))
+
+	out, err := Process(wav, "riff-24khz-16bit-mono-pcm", Processing{
+		TrimThreshold:  -50,
+		TargetLoudness: -16,
+		PadStart:       50 * time.Millisecond,
+		PadEnd:         50 * time.Millisecond,
+	})
+	if assert.NoError(t, err) {
+		_, samples, err := ParseWAV(out)
+		if assert.NoError(t, err) {
+			linear := Decode(samples, EncodingPCM)
+			assert.InDelta(t, 1200+len(quiet)+1200, len(linear), 480)
+			assert.InDelta(t, -16, Loudness(linear, 24000), 0.2)
+		}
+	}
+
+	mulaw := Encode(quiet, EncodingMuLaw)
+	out, err = Process(mulaw, "raw-24khz-8bit-mono-mulaw", Processing{TargetLevel: -20})
+	if assert.NoError(t, err) {
+		assert.Len(t, out, len(mulaw))
+		assert.InDelta(t, -20, Level(Decode(out, EncodingMuLaw)), 0.2)
+	}
+
+	_, err = Process(wav, "audio-24khz-48kbitrate-mono-mp3", Processing{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package audio
+
+import "math"
+
+// resampleZeroCrossings is the half-width of the resampling filter in zero crossings of its sinc. More zero
+// crossings give a steeper cutoff at the cost of speed.
+const resampleZeroCrossings = 16
+
+// resampleCutoff places the cutoff of the filter slightly below the Nyquist frequency of the lower rate, so that
+// the transition band does not alias.
+const resampleCutoff = 0.95
+
+// Resample converts samples from rate from to rate to. A windowed-sinc low-pass filter removes the frequencies
+// above the Nyquist frequency of the lower rate, which would otherwise alias when downsampling.
+func Resample(samples []int16, from, to int) []int16 {
+	if from == to || len(samples) == 0 {
+		return append([]int16(nil), samples...)
+	}
+	ratio := float64(to) / float64(from)
+	// cutoff in cycles per input sample, relative to the input Nyquist frequency.
+	cutoff := resampleCutoff * min(1, ratio)
+	halfWidth := resampleZeroCrossings / cutoff // in input samples
+
+	out := make([]int16, int(int64(len(samples))*int64(to)/int64(from)))
+	for n := range out {
+		t := float64(n) / ratio // position of the output sample in the input
+		first := max(0, int(math.Ceil(t-halfWidth)))
+		last := min(len(samples)-1, int(math.Floor(t+halfWidth)))
+		var sum float64
+		for k := first; k <= last; k++ {
+			x := t - float64(k)
+			sum += float64(samples[k]) * cutoff * sinc(cutoff*x) * blackman(x/halfWidth)
+		}
+		out[n] = clip16(sum)
+	}
+	return out
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman is the Blackman window on [-1, 1].
+func blackman(x float64) float64 {
+	if x <= -1 || x >= 1 {
+		return 0
+	}
+	return 0.42 + 0.5*math.Cos(math.Pi*x) + 0.08*math.Cos(2*math.Pi*x)
+}
+
+// clip16 rounds v to the nearest 16 bit sample.
+func clip16(v float64) int16 {
+	return int16(max(math.MinInt16, min(math.MaxInt16, math.Round(v))))
+}
@@ -0,0 +1,53 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sine returns d seconds of a tone of frequency hz at rate, with peak amplitude 10000.
+func sine(hz float64, rate int, d float64) []int16 {
+	samples := make([]int16, int(d*float64(rate)))
+	for i := range samples {
+		samples[i] = int16(10000 * math.Sin(2*math.Pi*hz*float64(i)/float64(rate)))
+	}
+	return samples
+}
+
+// rms returns the root mean square of samples, skipping the filter's ramp at both ends.
+func rms(samples []int16) float64 {
+	samples = samples[len(samples)/10 : len(samples)*9/10]
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
+
+func TestResample(t *testing.T) {
+	in := sine(1000, 24000, 0.5)
+	for _, to := range []int{8000, 16000, 22050, 48000} {
+		out := Resample(in, 24000, to)
+		assert.Len(t, out, to/2)
+		// a tone in the passband keeps its level and frequency.
+		assert.InDelta(t, rms(in), rms(out), rms(in)*0.01, "%d Hz", to)
+		want := sine(1000, to, 0.5)
+		assert.InDelta(t, 0, rms(subtract(out, want))/rms(want), 0.02, "%d Hz", to)
+	}
+
+	// a tone above the Nyquist frequency of the target rate is filtered instead of aliasing to 2 kHz.
+	out := Resample(sine(6000, 24000, 0.5), 24000, 8000)
+	assert.Less(t, rms(out), 10000*0.01)
+
+	assert.Equal(t, in, Resample(in, 24000, 24000))
+}
+
+func subtract(a, b []int16) []int16 {
+	d := make([]int16, min(len(a), len(b)))
+	for i := range d {
+		d[i] = a[i] - b[i]
+	}
+	return d
+}
@@ -0,0 +1,35 @@
+package audio
+
+// Transcode converts data from the format named from to the format named to, see ParseFormat.
+func Transcode(data []byte, from, to string) ([]byte, error) {
+	src, err := ParseFormat(from)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := ParseFormat(to)
+	if err != nil {
+		return nil, err
+	}
+	return Convert(data, src, dst)
+}
+
+// Convert converts data from format src to format dst. The sample rate of a WAV file is taken from its
+// header.
+func Convert(data []byte, src, dst Format) ([]byte, error) {
+	samples := data
+	if src.WAV {
+		var err error
+		if src, samples, err = ParseWAV(data); err != nil {
+			return nil, err
+		}
+	}
+	if src.SampleRate != dst.SampleRate || src.Encoding != dst.Encoding {
+		linear := Decode(samples, src.Encoding)
+		linear = Resample(linear, src.SampleRate, dst.SampleRate)
+		samples = Encode(linear, dst.Encoding)
+	}
+	if dst.WAV {
+		return EncodeWAV(dst, samples), nil
+	}
+	return samples, nil
+}
@@ -0,0 +1,59 @@
+package audio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{
+		"riff-24khz-16bit-mono-pcm":  {WAV: true, SampleRate: 24000, Encoding: EncodingPCM},
+		"raw-22050hz-16bit-mono-pcm": {SampleRate: 22050, Encoding: EncodingPCM},
+		"raw-8khz-8bit-mono-mulaw":   {SampleRate: 8000, Encoding: EncodingMuLaw},
+		"riff-8khz-8bit-mono-alaw":   {WAV: true, SampleRate: 8000, Encoding: EncodingALaw},
+	} {
+		f, err := ParseFormat(name)
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, want, f)
+			assert.Equal(t, name, f.String())
+		}
+	}
+	for _, name := range []string{"audio-24khz-48kbitrate-mono-mp3", "raw-16khz-16bit-mono-truesilk", "raw-8khz-16bit-mono-mulaw", "ogg-24khz-16bit-mono-opus"} {
+		_, err := ParseFormat(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestTranscode(t *testing.T) {
+	render := EncodeWAV(Format{WAV: true, SampleRate: 24000, Encoding: EncodingPCM}, Encode(sine(440, 24000, 1), EncodingPCM))
+
+	mulaw, err := Transcode(render, "riff-24khz-16bit-mono-pcm", "raw-8khz-8bit-mono-mulaw")
+	if assert.NoError(t, err) {
+		assert.Len(t, mulaw, 8000)
+		assert.InDelta(t, rms(sine(440, 8000, 1)), rms(Decode(mulaw, EncodingMuLaw)), 100)
+	}
+
+	wav, err := Transcode(render, "riff-24khz-16bit-mono-pcm", "riff-16khz-16bit-mono-pcm")
+	if assert.NoError(t, err) {
+		f, samples, err := ParseWAV(wav)
+		if assert.NoError(t, err) {
+			assert.Equal(t, Format{WAV: true, SampleRate: 16000, Encoding: EncodingPCM}, f)
+			assert.Len(t, samples, 32000)
+		}
+	}
+
+	alaw, err := Transcode(mulaw, "raw-8khz-8bit-mono-mulaw", "riff-8khz-8bit-mono-alaw")
+	if assert.NoError(t, err) {
+		f, samples, err := ParseWAV(alaw)
+		if assert.NoError(t, err) {
+			assert.Equal(t, Format{WAV: true, SampleRate: 8000, Encoding: EncodingALaw}, f)
+			assert.Len(t, samples, 8000)
+		}
+	}
+
+	_, err = Transcode(render[:20], "riff-24khz-16bit-mono-pcm", "raw-8khz-8bit-mono-mulaw")
+	assert.Error(t, err)
+	_, err = Transcode(render, "riff-24khz-16bit-mono-pcm", "audio-24khz-48kbitrate-mono-mp3")
+	assert.Error(t, err)
+}
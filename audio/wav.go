@@ -0,0 +1,90 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// WAV format tags of the encodings.
+const (
+	wavFormatPCM   = 1
+	wavFormatALaw  = 6
+	wavFormatMuLaw = 7
+)
+
+// ParseWAV returns the format and samples of a mono RIFF WAVE file.
+func ParseWAV(data []byte) (Format, []byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return Format{}, nil, fmt.Errorf("audio is not a RIFF WAVE file")
+	}
+	var f Format
+	for chunk := data[12:]; len(chunk) >= 8; {
+		size := int(binary.LittleEndian.Uint32(chunk[4:8]))
+		body := chunk[8:]
+		switch string(chunk[0:4]) {
+		case "fmt ":
+			if size < 16 || len(body) < 16 {
+				return Format{}, nil, fmt.Errorf("audio has a truncated fmt chunk")
+			}
+			tag := binary.LittleEndian.Uint16(body[0:2])
+			channels := binary.LittleEndian.Uint16(body[2:4])
+			bits := binary.LittleEndian.Uint16(body[14:16])
+			switch {
+			case tag == wavFormatPCM && bits == 16:
+				f.Encoding = EncodingPCM
+			case tag == wavFormatALaw && bits == 8:
+				f.Encoding = EncodingALaw
+			case tag == wavFormatMuLaw && bits == 8:
+				f.Encoding = EncodingMuLaw
+			default:
+				return Format{}, nil, fmt.Errorf("unsupported WAV format %d with %d bit samples", tag, bits)
+			}
+			if channels != 1 {
+				return Format{}, nil, fmt.Errorf("unsupported WAV with %d channels", channels)
+			}
+			f.WAV = true
+			f.SampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+		case "data":
+			if !f.WAV {
+				return Format{}, nil, fmt.Errorf("audio has no fmt chunk before its data")
+			}
+			// streamed files may declare a larger size than they have.
+			return f, body[:min(size, len(body))], nil
+		}
+		if size+size%2 > len(body) {
+			break
+		}
+		chunk = body[size+size%2:]
+	}
+	return Format{}, nil, fmt.Errorf("audio has no data chunk")
+}
+
+// EncodeWAV returns samples in format f wrapped in a RIFF WAVE header.
+func EncodeWAV(f Format, samples []byte) []byte {
+	tag := uint16(wavFormatPCM)
+	switch f.Encoding {
+	case EncodingALaw:
+		tag = wavFormatALaw
+	case EncodingMuLaw:
+		tag = wavFormatMuLaw
+	}
+	var header bytes.Buffer
+	header.WriteString("RIFF")
+	binary.Write(&header, binary.LittleEndian, uint32(36+len(samples)))
+	header.WriteString("WAVEfmt ")
+	for _, v := range []interface{}{
+		uint32(16), // size of the fmt chunk
+		tag,
+		uint16(1), // mono
+		uint32(f.SampleRate),
+		uint32(f.BytesPerSecond()),
+		uint16(f.BitsPerSample() / 8),
+		uint16(f.BitsPerSample()),
+	} {
+		binary.Write(&header, binary.LittleEndian, v)
+	}
+	header.WriteString("data")
+	binary.Write(&header, binary.LittleEndian, uint32(len(samples)))
+	return append(header.Bytes(), samples...)
+}
@@ -0,0 +1,175 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ebmlMagic is the ID of the EBML header starting every WebM file.
+var ebmlMagic = []byte{0x1A, 0x45, 0xDF, 0xA3}
+
+// IDs of the Matroska elements read by probeWebM.
+const (
+	ebmlSegment           = 0x18538067
+	ebmlInfo              = 0x1549A966
+	ebmlTimecodeScale     = 0x2AD7B1
+	ebmlDuration          = 0x4489
+	ebmlTracks            = 0x1654AE6B
+	ebmlTrackEntry        = 0xAE
+	ebmlCodecDelay        = 0x56AA
+	ebmlAudio             = 0xE1
+	ebmlSamplingFrequency = 0xB5
+	ebmlChannels          = 0x9F
+	ebmlCluster           = 0x1F43B675
+	ebmlTimecode          = 0xE7
+	ebmlBlockGroup        = 0xA0
+	ebmlBlock             = 0xA1
+	ebmlSimpleBlock       = 0xA3
+)
+
+// ebmlMasters are the elements whose children are read. They are walked as if their children followed them
+// at the same level, which also handles the unknown sizes of live streams.
+var ebmlMasters = map[uint32]bool{
+	ebmlSegment: true, ebmlInfo: true, ebmlTracks: true, ebmlTrackEntry: true, ebmlAudio: true,
+	ebmlCluster: true, ebmlBlockGroup: true,
+}
+
+// readVint reads an EBML variable length integer at the start of b, returning its value with or without the
+// length marker, its size and whether all value bits are set, which marks an unknown size.
+func readVint(b []byte, keepMarker bool) (value uint64, n int, unknown bool, ok bool) {
+	if len(b) == 0 || b[0] == 0 {
+		return 0, 0, false, false
+	}
+	n = 1
+	for mask := byte(0x80); b[0]&mask == 0; mask >>= 1 {
+		n++
+	}
+	if len(b) < n {
+		return 0, 0, false, false
+	}
+	value = uint64(b[0])
+	if !keepMarker {
+		value &= uint64(0xFF >> n)
+	}
+	for _, c := range b[1:n] {
+		value = value<<8 | uint64(c)
+	}
+	return value, n, value == 1<<(7*n)-1, true
+}
+
+// probeWebM returns the duration of a WebM file from the Duration of its segment, or else from the end of its
+// last block, as the files of streamed responses have no Duration.
+func probeWebM(data []byte) (Info, error) {
+	if len(data) < 4 || string(data[0:4]) != string(ebmlMagic) {
+		return Info{}, fmt.Errorf("audio is not a WebM file")
+	}
+	var (
+		info       Info
+		scale      int64         = 1000000 // nanoseconds per timecode, the default is 1 ms
+		duration                 = -1.0    // in timecodes
+		codecDelay int64                   // nanoseconds
+		cluster    int64                   // timecode of the current cluster
+		lastBlock  int64         = -1      // timecode of the last block
+		lastFrame  time.Duration           // duration of the last block
+	)
+	for offset := 0; offset < len(data); {
+		id, idSize, _, ok := readVint(data[offset:], true)
+		if !ok {
+			break
+		}
+		size, sizeSize, unknown, ok := readVint(data[offset+idSize:], false)
+		if !ok {
+			break
+		}
+		offset += idSize + sizeSize
+		if ebmlMasters[uint32(id)] {
+			continue
+		}
+		if unknown || offset+int(size) > len(data) {
+			// a truncated last element.
+			break
+		}
+		body := data[offset : offset+int(size)]
+		offset += int(size)
+
+		switch uint32(id) {
+		case ebmlTimecodeScale:
+			scale = int64(readUint(body))
+		case ebmlDuration:
+			duration = readFloat(body)
+		case ebmlCodecDelay:
+			codecDelay = int64(readUint(body))
+		case ebmlSamplingFrequency:
+			info.SampleRate = int(readFloat(body))
+		case ebmlChannels:
+			info.Channels = int(readUint(body))
+		case ebmlTimecode:
+			cluster = int64(readUint(body))
+		case ebmlSimpleBlock, ebmlBlock:
+			_, n, _, ok := readVint(body, false) // track number
+			if !ok || len(body) < n+4 {
+				continue
+			}
+			lastBlock = cluster + int64(int16(binary.BigEndian.Uint16(body[n:])))
+			lastFrame = opusPacketDuration(body[n+3:])
+		}
+	}
+
+	switch {
+	case duration >= 0:
+		info.Duration = time.Duration(duration * float64(scale))
+	case lastBlock >= 0:
+		info.Duration = time.Duration(lastBlock*scale) + lastFrame - time.Duration(codecDelay)
+	default:
+		return Info{}, fmt.Errorf("audio has neither a duration nor blocks")
+	}
+	info.Duration = max(0, info.Duration)
+	return info, nil
+}
+
+func readUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+func readFloat(b []byte) float64 {
+	switch len(b) {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// opusPacketDuration returns the duration of the Opus packet p from its TOC byte, see RFC 6716 section 3.1.
+func opusPacketDuration(p []byte) time.Duration {
+	if len(p) == 0 {
+		return 0
+	}
+	config := p[0] >> 3
+	var frame time.Duration
+	switch {
+	case config < 12: // SILK
+		frame = [4]time.Duration{10, 20, 40, 60}[config%4] * time.Millisecond
+	case config < 16: // hybrid
+		frame = [2]time.Duration{10, 20}[config%2] * time.Millisecond
+	default: // CELT
+		frame = [4]time.Duration{2500, 5000, 10000, 20000}[config%4] * time.Microsecond
+	}
+	switch p[0] & 3 {
+	case 0:
+		return frame
+	case 1, 2:
+		return 2 * frame
+	}
+	if len(p) < 2 {
+		return 0
+	}
+	return time.Duration(p[1]&0x3F) * frame
+}
@@ -0,0 +1,77 @@
+package azuretexttospeech
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/WqyJh/azuretexttospeech/audio"
+)
+
+// Probe returns the duration and sample layout of data, audio returned by the service in format. See
+// audio.Probe for how each format is measured; TrueSilk fails with audio.ErrUnsupported.
+func Probe(data []byte, format AudioOutput) (audio.Info, error) {
+	return audio.Probe(data, string(format))
+}
+
+// audioDuration returns the playing time of audio, or zero when it cannot be determined from the format.
+func audioDuration(data []byte, format AudioOutput) time.Duration {
+	info, err := Probe(data, format)
+	if err != nil {
+		return 0
+	}
+	return info.Duration
+}
+
+// streamedDuration returns the playing time of n bytes of audio, or zero when it cannot be determined from the
+// format. The RIFF header returned by the service is assumed to have the canonical size of 44 bytes.
+func streamedDuration(n int64, format AudioOutput) time.Duration {
+	f, err := audio.ParseFormat(string(format))
+	if err != nil {
+		return 0
+	}
+	if f.WAV {
+		n -= 44
+	}
+	if n <= 0 {
+		return 0
+	}
+	return f.Duration(int(n))
+}
+
+// Transcode converts data, audio in the uncompressed format from, to the uncompressed format to. A render can be
+// delivered in several formats this way, such as μ-law 8 kHz for telephony, without another billable request.
+// See the audio package.
+func Transcode(data []byte, from, to AudioOutput) ([]byte, error) {
+	return audio.Transcode(data, string(from), string(to))
+}
+
+// ContentType returns the media type of audio in format f.
+func (f AudioOutput) ContentType() string {
+	name := string(f)
+	switch {
+	case strings.HasPrefix(name, "riff-"):
+		return "audio/wav"
+	case strings.HasPrefix(name, "ogg-"):
+		return "audio/ogg"
+	case strings.HasPrefix(name, "webm-"):
+		return "audio/webm"
+	case strings.HasSuffix(name, "-mp3"):
+		return "audio/mpeg"
+	case strings.HasSuffix(name, "-opus"):
+		return "audio/opus"
+	case strings.HasPrefix(name, "amr-wb-"):
+		return "audio/AMR-WB"
+	}
+	if format, err := audio.ParseFormat(name); err == nil {
+		switch format.Encoding {
+		case audio.EncodingALaw:
+			return "audio/PCMA"
+		case audio.EncodingMuLaw:
+			return "audio/PCMU"
+		}
+		// little-endian samples, unlike the network byte order of audio/L16.
+		return fmt.Sprintf("audio/pcm;rate=%d", format.SampleRate)
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,23 @@
+package azuretexttospeech
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/WqyJh/azuretexttospeech/audio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbe(t *testing.T) {
+	format, _ := audio.ParseFormat(string(AudioOutput_riff_8khz_16bit_mono_pcm))
+	info, err := Probe(audio.EncodeWAV(format, make([]byte, 8000)), AudioOutput_riff_8khz_16bit_mono_pcm)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 500*time.Millisecond, info.Duration)
+		assert.Equal(t, 8000, info.SampleRate)
+		assert.Equal(t, 1, info.Channels)
+	}
+
+	_, err = Probe([]byte{0}, AudioOutput_raw_16khz_16bit_mono_truesilk)
+	assert.True(t, errors.Is(err, audio.ErrUnsupported))
+}
@@ -3,10 +3,16 @@ package azuretexttospeech
 import (
 	"bytes"
 	"context"
+	"encoding/xml"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 )
@@ -25,49 +31,217 @@ const tokenRefreshTimeout = time.Second * 15
 // text in which a user wishes to Synthesize, `region` is the language/locale, `gender` is the desired output voice
 // and `audioOutput` captures the audio format.
 func (az *AzureCSTextToSpeech) SynthesizeWithContext(ctx context.Context, param VoiceParam, audioOutput AudioOutput) ([]byte, error) {
+	result, err := az.SynthesizeResultWithContext(ctx, param, audioOutput)
+	if err != nil {
+		return nil, err
+	}
+	return result.Audio, nil
+}
+
+// SynthesizeResultWithContext is SynthesizeWithContext returning the audio together with the metadata of the response.
+func (az *AzureCSTextToSpeech) SynthesizeResultWithContext(ctx context.Context, param VoiceParam, audioOutput AudioOutput) (*SynthesisResult, error) {
+	return az.synthesizeParam(ctx, param, audioOutput, nil)
+}
+
+// SynthesizeToWriter is SynthesizeResultWithContext copying the audio to w as it is received instead of returning
+// it in the result, so that playback can start before the synthesis completes. The Duration of the result is
+// estimated from the size of uncompressed formats. A failure after the first write leaves partial audio in w.
+func (az *AzureCSTextToSpeech) SynthesizeToWriter(ctx context.Context, param VoiceParam, audioOutput AudioOutput, w io.Writer) (*SynthesisResult, error) {
+	return az.synthesizeParam(ctx, param, audioOutput, w)
+}
+
+func (az *AzureCSTextToSpeech) synthesizeParam(ctx context.Context, param VoiceParam, audioOutput AudioOutput, w io.Writer) (*SynthesisResult, error) {
+	v, err := voiceXMLRender(az.prepareParam(param))
+	if err != nil {
+		return nil, invalidRequest(fmt.Errorf("failed to render voiceXML, %v", err))
+	}
+	if err := az.validateVoiceStyle(ctx, param); err != nil {
+		return nil, err
+	}
+	return az.synthesize(ctx, v, param.Voice, audioOutput, w)
+}
+
+// SynthesizeSSMLWithContext returns the rendered text-to-speech of a caller supplied SSML document in the target
+// audio format. The document is checked with ParseSSML first, so invalid markup fails without a request.
+func (az *AzureCSTextToSpeech) SynthesizeSSMLWithContext(ctx context.Context, ssml string, audioOutput AudioOutput) ([]byte, error) {
+	result, err := az.SynthesizeSSMLResultWithContext(ctx, ssml, audioOutput)
+	if err != nil {
+		return nil, err
+	}
+	return result.Audio, nil
+}
+
+// SynthesizeSSMLResultWithContext is SynthesizeSSMLWithContext returning the audio together with the metadata of
+// the response.
+func (az *AzureCSTextToSpeech) SynthesizeSSMLResultWithContext(ctx context.Context, ssml string, audioOutput AudioOutput) (*SynthesisResult, error) {
+	return az.synthesizeSSML(ctx, ssml, audioOutput, nil)
+}
+
+// SynthesizeSSMLToWriter is SynthesizeToWriter for a caller supplied SSML document.
+func (az *AzureCSTextToSpeech) SynthesizeSSMLToWriter(ctx context.Context, ssml string, audioOutput AudioOutput, w io.Writer) (*SynthesisResult, error) {
+	return az.synthesizeSSML(ctx, ssml, audioOutput, w)
+}
 
-	v, err := voiceXMLRender(param)
+func (az *AzureCSTextToSpeech) synthesizeSSML(ctx context.Context, ssml string, audioOutput AudioOutput, w io.Writer) (*SynthesisResult, error) {
+	var opts []SSMLOption
+	if az.maxSSMLCharacters > 0 {
+		opts = append(opts, MaxBillableCharacters(az.maxSSMLCharacters))
+	}
+	doc, err := ParseSSML(ssml, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to render voiceXML, %v", err)
+		return nil, invalidRequest(fmt.Errorf("invalid SSML, %w", err))
+	}
+	return az.synthesize(ctx, ssml, strings.Join(doc.Voices(), ","), audioOutput, w)
+}
+
+// synthesize posts the SSML document, spoken by voice, to the text-to-speech endpoint, enforcing the quota and
+// recording the usage of the tenant of ctx when configured. The audio is copied to w when it is not nil.
+func (az *AzureCSTextToSpeech) synthesize(ctx context.Context, ssml, voice string, audioOutput AudioOutput, w io.Writer) (*SynthesisResult, error) {
+	ctx = withAttempts(ctx)
+	tenant := TenantFromContext(ctx)
+	characters, err := BillableCharacters(ssml)
+	if err != nil {
+		return nil, invalidRequest(fmt.Errorf("failed to count billable characters, %v", err))
+	}
+	if az.quota != nil {
+		if err := az.quota.Check(ctx, tenant, characters); err != nil {
+			return nil, err
+		}
+	}
+
+	attempt := func(ctx context.Context) (*SynthesisResult, error) {
+		if az.breaker != nil {
+			if err := az.breaker.allow(); err != nil {
+				return nil, err
+			}
+		}
+		var sent atomic.Bool
+		result, err := az.post(withSentCallback(ctx, func() { sent.Store(true) }), ssml, voice, audioOutput, w)
+		if az.breaker != nil {
+			az.breaker.done(err)
+		}
+		// a request abandoned after it was sent, such as the slower request of a hedge, may be billed as well.
+		if err == nil || (sent.Load() && ctx.Err() != nil) {
+			az.recordUsage(ctx, tenant, characters, result, audioOutput)
+		}
+		return result, err
+	}
+	var result *SynthesisResult
+	if az.hedger != nil && w == nil {
+		// audio already copied to w cannot be taken back, so streamed requests are not hedged.
+		result, err = az.hedger.run(ctx, characters, attempt, attempt)
+	} else {
+		result, err = attempt(ctx)
 	}
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, az.textToSpeechURL, bytes.NewBufferString(v))
+	if err != nil {
+		return nil, err
+	}
+	result.BillableCharacters = characters
+	return result, nil
+}
+
+// recordUsage records a request of characters for tenant with the quota and the usage meter. result is nil for
+// requests which were abandoned before their response arrived.
+func (az *AzureCSTextToSpeech) recordUsage(ctx context.Context, tenant string, characters int, result *SynthesisResult, audioOutput AudioOutput) {
+	// the request context may be canceled already, while the usage must still be stored.
+	ctx = context.WithoutCancel(ctx)
+	if az.quota != nil {
+		if err := az.quota.Record(ctx, tenant, characters); err != nil {
+			az.logger().LogAttrs(ctx, slog.LevelError, "failed to record quota usage",
+				slog.String("tenant", tenant), slog.Int("characters", characters), slog.Any("error", err))
+		}
+	}
+	if az.usageMeter != nil {
+		usage := Usage{
+			Tenant:     tenant,
+			Characters: characters,
+			Format:     audioOutput,
+			Time:       time.Now(),
+		}
+		if result != nil {
+			usage.AudioDuration = result.Duration
+		}
+		az.usageMeter.RecordUsage(ctx, usage)
+	}
+}
+
+// post sends the SSML document to the text-to-speech endpoint and returns the audio with the response metadata.
+// The audio is copied to w instead when it is not nil.
+func (az *AzureCSTextToSpeech) post(ctx context.Context, ssml, voice string, audioOutput AudioOutput, w io.Writer) (result *SynthesisResult, err error) {
+	attempt := nextAttempt(ctx)
+	ctx, op := az.telemetry().startOperation(ctx, operationSynthesize, attrVoice.String(voice), attrFormat.String(string(audioOutput)), attrAttempt.Int(attempt))
+	defer func() { op.end(ctx, err) }()
+	start := time.Now()
+	var firstByte time.Duration
+	traceCtx := httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				requestSent(ctx)
+			}
+		},
+		GotFirstResponseByte: func() {
+			firstByte = time.Since(start)
+			op.firstByte(ctx)
+		},
+	})
+
+	request, err := http.NewRequestWithContext(traceCtx, http.MethodPost, az.textToSpeechURL, bytes.NewBufferString(ssml))
 	if err != nil {
 		return nil, err
 	}
 	request.Header.Set("X-Microsoft-OutputFormat", fmt.Sprint(audioOutput))
 	request.Header.Set("Content-Type", "application/ssml+xml")
-	request.Header.Set("Authorization", "Bearer "+az.accessToken)
+	request.Header.Set("Authorization", "Bearer "+az.token())
 	request.Header.Set("User-Agent", "azuretts")
 
-	client := &http.Client{}
-	response, err := client.Do(request)
+	response, err := az.do(request, slog.Int("attempt", attempt))
 	if err != nil {
 		return nil, err
 	}
 	defer response.Body.Close()
+	op.status = response.StatusCode
+	gotHeaders(ctx)
 
 	// list of acceptable response status codes
 	// see: https://docs.microsoft.com/en-us/azure/cognitive-services/speech-service/rest-text-to-speech#http-status-codes-1
 	switch response.StatusCode {
 	case http.StatusOK:
 		// The request was successful; the response body is an audio file.
-		return io.ReadAll(response.Body)
+		if w != nil {
+			n, err := copyAudio(w, response.Body)
+			if err != nil {
+				return nil, err
+			}
+			op.span.SetAttributes(attrAudioBytes.Int64(n))
+			result = newSynthesisResult(response.Header, nil, audioOutput)
+			result.Duration = streamedDuration(n, audioOutput)
+			result.FirstByteLatency = firstByte
+			return result, nil
+		}
+		audio, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, err
+		}
+		op.span.SetAttributes(attrAudioBytes.Int(len(audio)))
+		result = newSynthesisResult(response.Header, audio, audioOutput)
+		result.FirstByteLatency = firstByte
+		return result, nil
 	case http.StatusBadRequest:
-		return nil, fmt.Errorf("%d - A required parameter is missing, empty, or null. Or, the value passed to either a required or optional parameter is invalid. A common issue is a header that is too long", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "A required parameter is missing, empty, or null. Or, the value passed to either a required or optional parameter is invalid. A common issue is a header that is too long"}
 	case http.StatusUnauthorized:
-		return nil, fmt.Errorf("%d - The request is not authorized. Check to make sure your subscription key or token is valid and in the correct region", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "The request is not authorized. Check to make sure your subscription key or token is valid and in the correct region"}
 	case http.StatusForbidden:
-		return nil, fmt.Errorf("%d - The request is forbidden. Check the voice name or other parameters", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "The request is forbidden. Check the voice name or other parameters"}
 	case http.StatusRequestEntityTooLarge:
-		return nil, fmt.Errorf("%d - The SSML input is longer than 1024 characters", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "The SSML input is longer than 1024 characters"}
 	case http.StatusUnsupportedMediaType:
-		return nil, fmt.Errorf("%d - It's possible that the wrong Content-Type was provided. Content-Type should be set to application/ssml+xml", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "It's possible that the wrong Content-Type was provided. Content-Type should be set to application/ssml+xml"}
 	case http.StatusTooManyRequests:
-		return nil, fmt.Errorf("%d - You have exceeded the quota or rate of requests allowed for your subscription", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "You have exceeded the quota or rate of requests allowed for your subscription"}
 	case http.StatusBadGateway:
-		return nil, fmt.Errorf("%d - Network or server-side issue. May also indicate invalid headers", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "Network or server-side issue. May also indicate invalid headers"}
 	default:
-		return nil, fmt.Errorf("%d - received unexpected HTTP status code", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "received unexpected HTTP status code"}
 	}
 }
 
@@ -78,26 +252,110 @@ func (az *AzureCSTextToSpeech) Synthesize(param VoiceParam, audioOutput AudioOut
 	return az.SynthesizeWithContext(ctx, param, audioOutput)
 }
 
-// TTSApiXMLPayload templates the payload required for API.
+// prepareParam applies the client-side text processing configured through options to param.
+func (az *AzureCSTextToSpeech) prepareParam(param VoiceParam) VoiceParam {
+	if az.normalizer != nil {
+		param.SpeechText = az.normalizer.Normalize(param.SpeechText, param.Locale)
+	}
+	if az.pronunciations != nil {
+		param.SpeechText = az.pronunciations.Apply(param.SpeechText, param.Locale)
+	}
+	return param
+}
+
+// TTSApiXMLPayload templates the payload required for API. The "voice" template renders a single VoiceParam.
 // See: https://docs.microsoft.com/en-us/azure/cognitive-services/speech-service/rest-text-to-speech#sample-request
-const ttsApiXMLTemplate = `<speak version='1.0' xml:lang='{{.Locale}}'><voice xml:lang='{{.Locale}}' xml:gender='{{.Gender}}' name='{{.Voice}}'>{{.SpeechText}}</voice></speak>`
+const ttsApiXMLTemplate = `{{define "voice"}}` +
+	`<voice xml:lang='{{.Locale}}' xml:gender='{{.Gender}}' name='{{.Voice}}'>` +
+	`{{range .LexiconURIs}}<lexicon uri='{{attr .}}'/>{{end}}` +
+	`{{if or .Style .Role}}<mstts:express-as{{if .Style}} style='{{attr .Style}}'{{end}}{{if .StyleDegree}} styledegree='{{float .StyleDegree}}'{{end}}{{if .Role}} role='{{attr .Role}}'{{end}}>{{end}}` +
+	`{{with .Prosody}}<prosody{{if .Rate}} rate='{{.Rate}}'{{end}}{{if .Pitch}} pitch='{{.Pitch}}'{{end}}` +
+	`{{if .Contour}} contour='{{contour .}}'{{end}}{{if .Volume}} volume='{{.Volume}}'{{end}}>{{end}}` +
+	`{{.SpeechText}}` +
+	`{{if .Prosody}}</prosody>{{end}}` +
+	`{{if or .Style .Role}}</mstts:express-as>{{end}}` +
+	`</voice>{{end}}` +
+	`<speak version='1.0'` +
+	`{{if .Mstts}} xmlns='http://www.w3.org/2001/10/synthesis' xmlns:mstts='https://www.w3.org/2001/mstts'{{end}}` +
+	` xml:lang='{{.Locale}}'>` +
+	`{{range .Voices}}{{template "voice" .}}{{end}}` +
+	`</speak>`
+
+// ssmlDocument is the data rendered by voiceXMLTemplate.
+type ssmlDocument struct {
+	Locale Locale
+	Mstts  bool // declare the mstts namespace used by `mstts:express-as`
+	Voices []VoiceParam
+}
 
 type VoiceParam struct {
 	SpeechText string
 	Voice      string
 	Locale     Locale
 	Gender     Gender
+	// LexiconURIs references publicly accessible custom lexicon files (see Lexicon) applied to SpeechText.
+	LexiconURIs []string
+	// Style, StyleDegree and Role select a speaking style of neural voices, rendered as `mstts:express-as`.
+	// The voice list of the region is used to check that the voice supports them.
+	// See: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/speech-synthesis-markup-voice#use-speaking-styles-and-roles
+	Style       string
+	StyleDegree float64 // intensity of Style from 0.01 to 2, zero uses the default intensity of 1
+	Role        string
+	// Prosody adjusts the rate, pitch and volume of the speech, nil keeps the voice's defaults.
+	Prosody *Prosody
 }
 
 var (
-	voiceXMLTemplate = template.Must(template.New("voiceXML").Parse(ttsApiXMLTemplate))
+	voiceXMLTemplate = template.Must(template.New("voiceXML").Funcs(template.FuncMap{"attr": xmlAttr, "float": formatFloat, "contour": (*Prosody).contour}).Parse(ttsApiXMLTemplate))
 )
 
+// xmlAttr escapes s for use as an XML attribute value.
+func xmlAttr(s string) string {
+	var result bytes.Buffer
+	xml.EscapeText(&result, []byte(s))
+	return result.String()
+}
+
+// formatFloat formats f without trailing zeros.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// validate checks the values of param which can be verified without calling the API.
+func (param VoiceParam) validate() error {
+	if param.StyleDegree != 0 && (param.StyleDegree < 0.01 || param.StyleDegree > 2) {
+		return fmt.Errorf("style degree %v is out of range, expected a value from 0.01 to 2", param.StyleDegree)
+	}
+	if param.StyleDegree != 0 && param.Style == "" {
+		return fmt.Errorf("style degree requires a style")
+	}
+	if param.Prosody != nil {
+		if err := param.Prosody.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // voiceXMLRender renders the XML payload for the TTS api.
 // For API reference see https://docs.microsoft.com/en-us/azure/cognitive-services/speech-service/rest-text-to-speech#sample-request
 func voiceXMLRender(param VoiceParam) (string, error) {
+	return voicesXMLRender(param.Locale, []VoiceParam{param})
+}
+
+// voicesXMLRender renders a payload speaking each of params in turn, using locale as the document language.
+func voicesXMLRender(locale Locale, params []VoiceParam) (string, error) {
+	doc := ssmlDocument{Locale: locale, Voices: params}
+	for _, param := range params {
+		if err := param.validate(); err != nil {
+			return "", err
+		}
+		if param.Style != "" || param.Role != "" {
+			doc.Mstts = true
+		}
+	}
 	var result bytes.Buffer
-	err := voiceXMLTemplate.Execute(&result, param)
+	err := voiceXMLTemplate.Execute(&result, doc)
 	if err != nil {
 		return "", err
 	}
@@ -110,18 +368,28 @@ func voiceXMLRender(param VoiceParam) (string, error) {
 // https://docs.microsoft.com/en-us/azure/cognitive-services/speech-service/rest-apis#authentication .
 // Note: This does not need to be called by a client, since this automatically runs via a background go-routine (`startRefresher`)
 func (az *AzureCSTextToSpeech) refreshToken() error {
-	request, err := http.NewRequest(http.MethodPost, az.tokenRefreshURL, nil)
+	return az.refreshTokenWithContext(context.Background())
+}
+
+// refreshTokenWithContext is refreshToken bounded by ctx in addition to tokenRefreshTimeout.
+func (az *AzureCSTextToSpeech) refreshTokenWithContext(ctx context.Context) (err error) {
+	ctx, op := az.telemetry().startOperation(ctx, operationTokenRefresh)
+	defer func() { op.end(ctx, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, tokenRefreshTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, az.tokenRefreshURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request, %v", err)
 	}
 	request.Header.Set("Ocp-Apim-Subscription-Key", az.SubscriptionKey)
-	client := &http.Client{Timeout: tokenRefreshTimeout}
 
-	response, err := client.Do(request)
+	response, err := az.do(request)
 	if err != nil {
 		return fmt.Errorf("failed to fetch token, %v", err)
 	}
 	defer response.Body.Close()
+	op.status = response.StatusCode
 
 	if response.StatusCode != http.StatusOK {
 		return fmt.Errorf("unexpected status code; received http status=%s", response.Status)
@@ -131,10 +399,19 @@ func (az *AzureCSTextToSpeech) refreshToken() error {
 	if err != nil {
 		return fmt.Errorf("failed to read response body, %v", err)
 	}
+	az.tokenMu.Lock()
 	az.accessToken = string(body)
+	az.tokenMu.Unlock()
 	return nil
 }
 
+// token returns the current access token, which the refresher and Verify may replace at any time.
+func (az *AzureCSTextToSpeech) token() string {
+	az.tokenMu.RLock()
+	defer az.tokenMu.RUnlock()
+	return az.accessToken
+}
+
 // startRefresher updates the authentication token on at a 9 minute interval. A channel is returned
 // if the caller wishes to cancel the channel.
 func (az *AzureCSTextToSpeech) startRefresher() chan bool {
@@ -147,7 +424,8 @@ func (az *AzureCSTextToSpeech) startRefresher() chan bool {
 			case <-ticker.C:
 				err := az.refreshToken()
 				if err != nil {
-					log.Printf("failed to refresh token, %v", err)
+					az.logger().LogAttrs(context.Background(), slog.LevelError, "failed to refresh token",
+						slog.String("url", az.tokenRefreshURL), slog.Any("error", err))
 				}
 			case <-done:
 				return
@@ -159,25 +437,31 @@ func (az *AzureCSTextToSpeech) startRefresher() chan bool {
 
 // AzureCSTextToSpeech stores configuration and state information for the TTS client.
 type AzureCSTextToSpeech struct {
+	tokenMu             sync.RWMutex
 	accessToken         string    // is the auth token received from `TokenRefreshAPI`. Used in the Authorization: Bearer header.
 	SubscriptionKey     string    // API key for Azure's Congnitive Speech services
 	TokenRefreshDoneCh  chan bool // channel to stop the token refresh goroutine.
 	tokenRefreshURL     string
 	voiceServiceListURL string
 	textToSpeechURL     string
-	client              *http.Client
+	client              *http.Client // sends all requests, see httpClient
+	pronunciations      *PronunciationDictionary
+	normalizer          Normalizer
+	voicesMu            sync.Mutex
+	voices              []Voice // voice list of the region, see voiceCatalog
+	usageMeter          UsageMeter
+	quota               *QuotaEnforcer
+	breaker             *CircuitBreaker
+	hedger              *Hedger
+	streamLookahead     int
+	maxSSMLCharacters   int // see WithMaxSSMLCharacters
+	tel                 *telemetry
+	log                 *slog.Logger
 }
 
-// New returns an AzureCSTextToSpeech object.
-func New(subscriptionKey string, region Region) (*AzureCSTextToSpeech, error) {
-	az := &AzureCSTextToSpeech{
-		SubscriptionKey: subscriptionKey,
-	}
-
-	az.textToSpeechURL = fmt.Sprintf(textToSpeechAPI, region)
-	az.tokenRefreshURL = fmt.Sprintf(tokenRefreshAPI, region)
-	az.voiceServiceListURL = fmt.Sprintf(voiceListAPI, region)
-	az.client = &http.Client{}
+// New returns an AzureCSTextToSpeech object. Optional behaviour is configured through opts.
+func New(subscriptionKey string, region Region, opts ...Option) (*AzureCSTextToSpeech, error) {
+	az := newClient(subscriptionKey, region, opts...)
 
 	// api requires that the token is refreshed every 10 mintutes.
 	// We will do this task in the background every ~9 minutes.
@@ -188,3 +472,17 @@ func New(subscriptionKey string, region Region) (*AzureCSTextToSpeech, error) {
 	az.TokenRefreshDoneCh = az.startRefresher()
 	return az, nil
 }
+
+// newClient returns a client of region which has not fetched a token yet.
+func newClient(subscriptionKey string, region Region, opts ...Option) *AzureCSTextToSpeech {
+	az := &AzureCSTextToSpeech{
+		SubscriptionKey:     subscriptionKey,
+		textToSpeechURL:     fmt.Sprintf(textToSpeechAPI, region),
+		tokenRefreshURL:     fmt.Sprintf(tokenRefreshAPI, region),
+		voiceServiceListURL: fmt.Sprintf(voiceListAPI, region),
+	}
+	for _, opt := range opts {
+		opt(az)
+	}
+	return az
+}
@@ -69,3 +69,39 @@ func TestRefreshToken(t *testing.T) {
 	assert.NoError(t, err, "should not return an error")
 	assert.Equal(t, az.SubscriptionKey, az.accessToken, "values should be equal")
 }
+
+func TestVoiceXMLLexicon(t *testing.T) {
+	expect := "<speak version='1.0' xml:lang='en-US'><voice xml:lang='en-US' xml:gender='Male' name='en-US-GuyNeural'>" +
+		"<lexicon uri='https://example.com/lexicon.xml?a=1&amp;b=2'/>BTW, Contoso.</voice></speak>"
+	xml, err := voiceXMLRender(VoiceParam{
+		SpeechText:  "BTW, Contoso.",
+		Voice:       "en-US-GuyNeural",
+		Locale:      LocaleEnUS,
+		Gender:      GenderMale,
+		LexiconURIs: []string{"https://example.com/lexicon.xml?a=1&b=2"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, expect, xml)
+}
+
+func TestVoiceXMLStyle(t *testing.T) {
+	expect := "<speak version='1.0' xmlns='http://www.w3.org/2001/10/synthesis' xmlns:mstts='https://www.w3.org/2001/mstts' xml:lang='zh-CN'>" +
+		"<voice xml:lang='zh-CN' xml:gender='Female' name='zh-CN-XiaomoNeural'>" +
+		"<mstts:express-as style='sad' styledegree='1.5' role='YoungAdultFemale'>test</mstts:express-as></voice></speak>"
+	xml, err := voiceXMLRender(VoiceParam{
+		SpeechText:  "test",
+		Voice:       "zh-CN-XiaomoNeural",
+		Locale:      LocaleZhCN,
+		Gender:      GenderFemale,
+		Style:       "sad",
+		StyleDegree: 1.5,
+		Role:        "YoungAdultFemale",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, expect, xml)
+
+	_, err = voiceXMLRender(VoiceParam{Style: "sad", StyleDegree: 3})
+	assert.Error(t, err, "style degree above 2 should be rejected")
+	_, err = voiceXMLRender(VoiceParam{StyleDegree: 1})
+	assert.Error(t, err, "style degree without a style should be rejected")
+}
@@ -0,0 +1,98 @@
+package azuretexttospeech
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BillableCharacters counts the characters Azure bills for synthesizing an SSML document. Everything inside
+// the `<speak>` element is billable, including markup, except the `<speak>` and `<voice>` tags themselves.
+// Chinese characters, including the kanji of Japanese and the hanja of Korean, count as two characters.
+// See: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/text-to-speech#pricing-note
+func BillableCharacters(ssml string) (int, error) {
+	d := xml.NewDecoder(strings.NewReader(ssml))
+	n := 0
+	offset := d.InputOffset()
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		raw := ssml[offset:d.InputOffset()]
+		offset = d.InputOffset()
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "speak" || t.Name.Local == "voice" {
+				continue
+			}
+		case xml.EndElement:
+			if t.Name.Local == "speak" || t.Name.Local == "voice" {
+				continue
+			}
+		case xml.CharData:
+		default:
+			// the XML declaration, comments and directives are not part of the speech.
+			continue
+		}
+		n += billableLength(raw)
+	}
+}
+
+// billableLength counts the runes of s, counting Han characters twice. Kana and Hangul count once.
+func billableLength(s string) int {
+	n := utf8.RuneCountInString(s)
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			n++
+		}
+	}
+	return n
+}
+
+// BillableCharacters counts the characters Azure bills for synthesizing param, see BillableCharacters.
+// Processing configured on a client, such as a Normalizer, is not taken into account.
+func (param VoiceParam) BillableCharacters() (int, error) {
+	ssml, err := voiceXMLRender(param)
+	if err != nil {
+		return 0, err
+	}
+	return BillableCharacters(ssml)
+}
+
+// billableCharacters counts the characters billed for synthesizing param with this client's processing.
+func (az *AzureCSTextToSpeech) billableCharacters(param VoiceParam) (int, error) {
+	return az.prepareParam(param).BillableCharacters()
+}
+
+// CostEstimator converts billable characters to a price.
+type CostEstimator struct {
+	StandardPerMillion float64 // price of one million characters spoken by standard voices
+	NeuralPerMillion   float64 // price of one million characters spoken by neural voices
+}
+
+// DefaultCostEstimator uses the pay-as-you-go list prices in USD at the time of writing. Configure a
+// CostEstimator with the prices of your agreement and currency for accurate figures.
+// See: https://azure.microsoft.com/en-us/pricing/details/cognitive-services/speech-services/
+var DefaultCostEstimator = CostEstimator{StandardPerMillion: 4, NeuralPerMillion: 16}
+
+// Estimate returns the price of synthesizing characters billable characters with a voice of the given type.
+func (c CostEstimator) Estimate(characters int, voiceType VoiceType) float64 {
+	price := c.StandardPerMillion
+	if voiceType == VoiceNeural {
+		price = c.NeuralPerMillion
+	}
+	return float64(characters) * price / 1e6
+}
+
+// EstimateVoice is Estimate for a voice of the voice list, priced by its VoiceType. Use LookupVoice to find
+// the voice of a name.
+func (c CostEstimator) EstimateVoice(characters int, voice Voice) float64 {
+	return c.Estimate(characters, voice.VoiceType)
+}
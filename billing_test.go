@@ -0,0 +1,43 @@
+package azuretexttospeech
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBillableCharacters(t *testing.T) {
+	tests := []struct {
+		ssml   string
+		expect int
+	}{
+		{"<speak version='1.0' xml:lang='en-US'><voice name='a'>Hello, world!</voice></speak>", 13},
+		{"<?xml version='1.0'?><speak version='1.0' xml:lang='en-US'>\n<voice name='a'>Hi</voice></speak>", 3},
+		{"<speak version='1.0' xml:lang='en-US'><voice name='a'>Hi<break time='1s'/></voice></speak>", 2 + len("<break time='1s'/>")},
+		{"<speak version='1.0' xml:lang='zh-CN'><voice name='a'>你好, ok</voice></speak>", 4 + 4},
+		{"<speak version='1.0' xml:lang='ja-JP'><voice name='a'>日本語です</voice></speak>", 3*2 + 2},
+		{"<speak version='1.0' xml:lang='ja-JP'><voice name='a'>こんにちは</voice></speak>", 5},
+		{"<speak version='1.0' xml:lang='ko-KR'><voice name='a'>안녕</voice><!-- comment --></speak>", 2},
+	}
+	for _, tt := range tests {
+		n, err := BillableCharacters(tt.ssml)
+		assert.NoError(t, err, tt.ssml)
+		assert.Equal(t, tt.expect, n, tt.ssml)
+	}
+
+	_, err := BillableCharacters("<speak><voice>")
+	assert.Error(t, err)
+
+	n, err := VoiceParam{SpeechText: "Hello", Voice: "en-US-AvaNeural", Locale: LocaleEnUS, Prosody: &Prosody{Rate: RateSlow}}.BillableCharacters()
+	assert.NoError(t, err)
+	assert.Equal(t, len("<prosody rate='slow'>Hello</prosody>"), n)
+}
+
+func TestCostEstimator(t *testing.T) {
+	c := CostEstimator{StandardPerMillion: 4, NeuralPerMillion: 16}
+	assert.InDelta(t, 4.0, c.Estimate(1000000, VoiceStandard), 1e-9)
+	assert.InDelta(t, 0.016, c.Estimate(1000, VoiceNeural), 1e-9)
+	assert.InDelta(t, 0.016, c.EstimateVoice(1000, Voice{ShortName: "en-US-AvaNeural", VoiceType: VoiceNeural}), 1e-9)
+	assert.InDelta(t, 0.004, c.EstimateVoice(1000, Voice{ShortName: "ar-EG-Hoda", VoiceType: VoiceStandard}), 1e-9)
+	assert.InDelta(t, 0.004, c.EstimateVoice(1000, Voice{ShortName: "en-US-CustomNeural", VoiceType: VoiceStandard}), 1e-9, "the tier comes from the voice list, not the name")
+}
@@ -0,0 +1,169 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending a request while the circuit breaker of the client is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+//
+//go:generate enumer -type=CircuitState -linecomment -json
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // closed
+	CircuitOpen                         // open
+	CircuitHalfOpen                     // half-open
+)
+
+// Defaults of a CircuitBreaker.
+const (
+	defaultFailureRatio     = 0.5
+	defaultMinRequests      = 10
+	defaultBreakerWindow    = time.Minute
+	defaultBreakerCooldown  = 30 * time.Second
+	defaultHalfOpenRequests = 1
+)
+
+// CircuitBreaker stops sending synthesis requests while the Speech service is failing, see WithCircuitBreaker.
+// The circuit opens when the ratio of failed requests during Window reaches FailureRatio. While open, requests
+// fail fast with ErrCircuitOpen. After Cooldown the circuit is half-open and lets HalfOpenRequests requests
+// through: a success closes the circuit, a failure opens it again.
+//
+// Network errors, timeouts, server errors and throttling count as failures. Errors caused by the request,
+// such as invalid SSML, count as successes, and canceled requests are ignored. Zero fields use the defaults
+// given in their comments.
+type CircuitBreaker struct {
+	FailureRatio     float64       // default 0.5
+	MinRequests      int           // requests during Window before the ratio is considered, default 10
+	Window           time.Duration // default one minute
+	Cooldown         time.Duration // default 30 seconds
+	HalfOpenRequests int           // default 1
+	// OnStateChange is called on every transition, for example to alert. It must not block.
+	OnStateChange func(from, to CircuitState)
+
+	mu          sync.Mutex
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	trials      int // requests in flight while half-open
+	now         func() time.Time
+}
+
+func (b *CircuitBreaker) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
+
+func orDefault[T int | float64 | time.Duration](v, def T) T {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	state, notify := b.advance()
+	b.mu.Unlock()
+	if notify != nil {
+		notify()
+	}
+	return state
+}
+
+// advance moves an open circuit whose cooldown elapsed to half-open. It returns the state and the
+// transition, if any, to be reported after unlocking.
+func (b *CircuitBreaker) advance() (CircuitState, func()) {
+	if b.state == CircuitOpen && b.clock().Sub(b.openedAt) >= orDefault(b.Cooldown, defaultBreakerCooldown) {
+		return CircuitHalfOpen, b.setState(CircuitHalfOpen)
+	}
+	return b.state, nil
+}
+
+// setState changes the state, returning the OnStateChange call for the transition.
+func (b *CircuitBreaker) setState(state CircuitState) func() {
+	from := b.state
+	b.state = state
+	b.requests, b.failures, b.trials = 0, 0, 0
+	b.windowStart = b.clock()
+	if state == CircuitOpen {
+		b.openedAt = b.windowStart
+	}
+	if b.OnStateChange == nil || from == state {
+		return nil
+	}
+	return func() { b.OnStateChange(from, state) }
+}
+
+// allow returns ErrCircuitOpen when a request may not be sent. Otherwise the outcome of the request must be
+// reported with done.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	state, notify := b.advance()
+	var err error
+	switch state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.trials >= orDefault(b.HalfOpenRequests, defaultHalfOpenRequests) {
+			err = ErrCircuitOpen
+		} else {
+			b.trials++
+		}
+	}
+	b.mu.Unlock()
+	if notify != nil {
+		notify()
+	}
+	return err
+}
+
+// done records the outcome of a request admitted by allow.
+func (b *CircuitBreaker) done(err error) {
+	canceled := errors.Is(err, context.Canceled)
+	failed := isServiceFailure(err)
+
+	b.mu.Lock()
+	var notify func()
+	switch b.state {
+	case CircuitHalfOpen:
+		switch {
+		case failed:
+			notify = b.setState(CircuitOpen)
+		case canceled:
+			b.trials--
+		default:
+			notify = b.setState(CircuitClosed)
+		}
+	case CircuitClosed:
+		if canceled {
+			break
+		}
+		if now := b.clock(); now.Sub(b.windowStart) >= orDefault(b.Window, defaultBreakerWindow) {
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+		b.requests++
+		if failed {
+			b.failures++
+		}
+		if b.requests >= orDefault(b.MinRequests, defaultMinRequests) &&
+			float64(b.failures)/float64(b.requests) >= orDefault(b.FailureRatio, defaultFailureRatio) {
+			notify = b.setState(CircuitOpen)
+		}
+	}
+	b.mu.Unlock()
+	if notify != nil {
+		notify()
+	}
+}
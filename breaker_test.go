@@ -0,0 +1,83 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+	var transitions []string
+	b := &CircuitBreaker{
+		MinRequests: 4,
+		Cooldown:    10 * time.Second,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, fmt.Sprintf("%s->%s", from, to))
+		},
+		now: func() time.Time { return now },
+	}
+	unavailable := &StatusError{StatusCode: http.StatusServiceUnavailable}
+	badRequest := &StatusError{StatusCode: http.StatusBadRequest}
+
+	for _, err := range []error{nil, badRequest, unavailable, context.Canceled, unavailable} {
+		assert.NoError(t, b.allow())
+		b.done(err)
+	}
+	assert.Equal(t, CircuitOpen, b.State(), "2 of 4 requests failed, canceled requests are ignored")
+	assert.True(t, errors.Is(b.allow(), ErrCircuitOpen))
+
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, CircuitHalfOpen, b.State())
+	assert.NoError(t, b.allow())
+	assert.True(t, errors.Is(b.allow(), ErrCircuitOpen), "only one trial request while half-open")
+	b.done(unavailable)
+	assert.Equal(t, CircuitOpen, b.State())
+
+	now = now.Add(10 * time.Second)
+	assert.NoError(t, b.allow())
+	b.done(badRequest)
+	assert.Equal(t, CircuitClosed, b.State())
+
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}, transitions)
+
+	// failures of an elapsed window are forgotten.
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, b.allow())
+		b.done(unavailable)
+	}
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, b.allow())
+		b.done(nil)
+	}
+	assert.Equal(t, CircuitClosed, b.State())
+}
+
+func TestSynthesizeCircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL}
+	WithCircuitBreaker(&CircuitBreaker{MinRequests: 2})(az)
+	param := VoiceParam{SpeechText: "Hello", Voice: "en-US-AvaNeural", Locale: LocaleEnUS}
+	for i := 0; i < 4; i++ {
+		_, err := az.SynthesizeWithContext(context.Background(), param, AudioOutput_riff_8khz_16bit_mono_pcm)
+		assert.Error(t, err)
+		if i >= 2 {
+			assert.True(t, errors.Is(err, ErrCircuitOpen))
+		}
+	}
+	assert.Equal(t, int32(2), requests.Load())
+}
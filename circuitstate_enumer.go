@@ -0,0 +1,103 @@
+// Code generated by "enumer -type=CircuitState -linecomment -json"; DO NOT EDIT.
+
+package azuretexttospeech
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const _CircuitStateName = "closedopenhalf-open"
+
+var _CircuitStateIndex = [...]uint8{0, 6, 10, 19}
+
+const _CircuitStateLowerName = "closedopenhalf-open"
+
+func (i CircuitState) String() string {
+	if i < 0 || i >= CircuitState(len(_CircuitStateIndex)-1) {
+		return fmt.Sprintf("CircuitState(%d)", i)
+	}
+	return _CircuitStateName[_CircuitStateIndex[i]:_CircuitStateIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _CircuitStateNoOp() {
+	var x [1]struct{}
+	_ = x[CircuitClosed-(0)]
+	_ = x[CircuitOpen-(1)]
+	_ = x[CircuitHalfOpen-(2)]
+}
+
+var _CircuitStateValues = []CircuitState{CircuitClosed, CircuitOpen, CircuitHalfOpen}
+
+var _CircuitStateNameToValueMap = map[string]CircuitState{
+	_CircuitStateName[0:6]:   CircuitClosed,
+	_CircuitStateName[6:10]:  CircuitOpen,
+	_CircuitStateName[10:19]: CircuitHalfOpen,
+}
+
+var _CircuitStateLowerNameToValueMap = map[string]CircuitState{
+	_CircuitStateLowerName[0:6]:   CircuitClosed,
+	_CircuitStateLowerName[6:10]:  CircuitOpen,
+	_CircuitStateLowerName[10:19]: CircuitHalfOpen,
+}
+
+var _CircuitStateNames = []string{
+	_CircuitStateName[0:6],
+	_CircuitStateName[6:10],
+	_CircuitStateName[10:19],
+}
+
+// CircuitStateString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func CircuitStateString(s string) (CircuitState, error) {
+	if val, ok := _CircuitStateNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _CircuitStateLowerNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to CircuitState values", s)
+}
+
+// CircuitStateValues returns all values of the enum
+func CircuitStateValues() []CircuitState {
+	return _CircuitStateValues
+}
+
+// CircuitStateStrings returns a slice of all String values of the enum
+func CircuitStateStrings() []string {
+	strs := make([]string, len(_CircuitStateNames))
+	copy(strs, _CircuitStateNames)
+	return strs
+}
+
+// IsACircuitState returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i CircuitState) IsACircuitState() bool {
+	for _, v := range _CircuitStateValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for CircuitState
+func (i CircuitState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for CircuitState
+func (i *CircuitState) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("CircuitState should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = CircuitStateString(s)
+	return err
+}
@@ -0,0 +1,140 @@
+// Command azuretts runs Azure text-to-speech tasks.
+//
+//	azuretts serve [-addr :8080] [-grpc-addr :9090] [-format audio-24khz-48kbitrate-mono-mp3] [-health-voice en-US-AvaNeural]
+//
+// The subscription key and region are read from the AZURE_KEY and AZURE_REGION environment variables.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	tts "github.com/WqyJh/azuretexttospeech"
+	"github.com/WqyJh/azuretexttospeech/server"
+	"github.com/WqyJh/azuretexttospeech/ttsgrpc"
+	"google.golang.org/grpc"
+)
+
+const usage = `usage: azuretts <command> [flags]
+
+commands:
+  serve    serve synthesis over HTTP and optionally gRPC
+`
+
+func exit(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %+v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "serve":
+		exit(serve(os.Args[2:]))
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// newClient returns a client configured by the environment.
+func newClient() (*tts.AzureCSTextToSpeech, error) {
+	var apiKey, region string
+	if apiKey = os.Getenv("AZURE_KEY"); apiKey == "" {
+		return nil, fmt.Errorf("please set your AZURE_KEY environment variable")
+	}
+	if region = os.Getenv("AZURE_REGION"); region == "" {
+		return nil, fmt.Errorf("please set your AZURE_REGION environment variable")
+	}
+	az, err := tts.New(apiKey, tts.Region(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new client, received %v", err)
+	}
+	return az, nil
+}
+
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	grpcAddr := flags.String("grpc-addr", "", "address to serve the gRPC service on, disabled when empty")
+	format := flags.String("format", "", "default audio format, see GET /formats")
+	var healthVoices stringList
+	flags.Var(&healthVoices, "health-voice", "voice which must be available for /healthz to pass, may be repeated")
+	flags.Parse(args)
+
+	opts := []server.Option{server.WithHealthVoices(healthVoices...)}
+	if *format != "" {
+		if !slices.Contains(tts.AudioOutputs, tts.AudioOutput(*format)) {
+			return fmt.Errorf("unsupported format %q", *format)
+		}
+		opts = append(opts, server.WithDefaultFormat(tts.AudioOutput(*format)))
+	}
+
+	az, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer close(az.TokenRefreshDoneCh)
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(az, opts...),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	var grpcSrv *grpc.Server
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			return err
+		}
+		grpcSrv = grpc.NewServer()
+		ttsgrpc.RegisterTextToSpeechServer(grpcSrv, ttsgrpc.NewServer(az))
+		fmt.Fprintf(os.Stderr, "serving gRPC on %s\n", *grpcAddr)
+		go grpcSrv.Serve(lis)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		if grpcSrv != nil {
+			grpcSrv.GracefulStop()
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "listening on %s\n", *addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// stringList is a flag which may be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return fmt.Sprint(*l)
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
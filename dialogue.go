@@ -0,0 +1,245 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/WqyJh/azuretexttospeech/audio"
+)
+
+// The following are the limits of a single synthesis request.
+// See: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/speech-services-quotas-and-limits#text-to-speech-quotas-and-limits-per-speech-resource
+const (
+	maxVoiceElements = 50
+	maxSSMLSize      = 64 * 1024
+)
+
+// maxBreak is the longest pause a single `<break>` element can produce.
+const maxBreak = 20 * time.Second
+
+// dialogueConcurrency is the number of documents SynthesizeDialogue renders at the same time.
+const dialogueConcurrency = 4
+
+// DialogueTurn is a line of a dialogue script spoken by the voice of the embedded VoiceParam, followed by
+// an optional Pause.
+type DialogueTurn struct {
+	VoiceParam
+	Pause time.Duration
+}
+
+// EstimatedTurnOffset is the estimated position of the speech of a turn in the audio returned by
+// SynthesizeDialogue. Duration does not include the pause following the turn. The REST API does not report
+// where a voice starts, so the speech of a document is shared among its turns by their billable characters;
+// only the pauses and the boundaries of documents are exact. Synthesize the turns one by one to measure them.
+type EstimatedTurnOffset struct {
+	Offset   time.Duration
+	Duration time.Duration
+}
+
+// DialogueResult is the audio of a dialogue with the estimated position of each turn.
+type DialogueResult struct {
+	Audio              []byte
+	EstimatedTurns     []EstimatedTurnOffset
+	BillableCharacters int // characters billed for all requests of the dialogue
+}
+
+// dialogueDocument is an SSML document of a dialogue holding turns consecutive turns.
+type dialogueDocument struct {
+	ssml  string
+	turns int
+}
+
+// DialogueSSML renders turns as SSML documents with one `<voice>` element per turn. A new document is started
+// whenever a document would exceed the voice element or size limits of a request, so most scripts render to
+// a single document. The document language is the locale of the first turn.
+func (az *AzureCSTextToSpeech) DialogueSSML(turns []DialogueTurn) ([]string, error) {
+	documents, err := az.dialogueDocuments(turns)
+	if err != nil {
+		return nil, err
+	}
+	ssml := make([]string, len(documents))
+	for i, d := range documents {
+		ssml[i] = d.ssml
+	}
+	return ssml, nil
+}
+
+func (az *AzureCSTextToSpeech) dialogueDocuments(turns []DialogueTurn) ([]dialogueDocument, error) {
+	if len(turns) == 0 {
+		return nil, fmt.Errorf("dialogue has no turns")
+	}
+
+	var documents []dialogueDocument
+	var params []VoiceParam
+	var rendered string
+	for i, turn := range turns {
+		if err := turn.validate(); err != nil {
+			return nil, invalidRequest(fmt.Errorf("turn %d is invalid, %v", i, err))
+		}
+		param := az.prepareParam(turn.VoiceParam)
+		param.SpeechText += breakXML(turn.Pause)
+
+		next, err := voicesXMLRender(turns[0].Locale, append(params, param))
+		if err != nil {
+			return nil, fmt.Errorf("failed to render turn %d, %v", i, err)
+		}
+		if len(params) > 0 && (len(params) == maxVoiceElements || len(next) > maxSSMLSize) {
+			documents = append(documents, dialogueDocument{ssml: rendered, turns: len(params)})
+			params = nil
+			if next, err = voicesXMLRender(turns[0].Locale, []VoiceParam{param}); err != nil {
+				return nil, fmt.Errorf("failed to render turn %d, %v", i, err)
+			}
+		}
+		if len(next) > maxSSMLSize {
+			return nil, fmt.Errorf("turn %d is longer than the %d byte request limit", i, maxSSMLSize)
+		}
+		params = append(params, param)
+		rendered = next
+	}
+	return append(documents, dialogueDocument{ssml: rendered, turns: len(params)}), nil
+}
+
+// breakXML renders `<break>` elements pausing for d.
+func breakXML(d time.Duration) string {
+	var result string
+	for d > 0 {
+		pause := d
+		if pause > maxBreak {
+			pause = maxBreak
+		}
+		result += fmt.Sprintf("<break time='%dms'/>", pause.Milliseconds())
+		d -= pause
+	}
+	return result
+}
+
+// SynthesizeDialogue renders a multi-voice script as a single audio file. The script is sent as the documents
+// of DialogueSSML, usually a single request. Scripts exceeding the limits of a request are rendered by several
+// requests (up to dialogueConcurrency at a time) whose audio is joined locally, which requires an uncompressed
+// format, such as AudioOutput_riff_24khz_16bit_mono_pcm or AudioOutput_raw_8khz_8bit_mono_mulaw; such scripts
+// fail in other formats. The styles and roles of the turns are checked against the voice list first. Turn
+// offsets are estimated, see EstimatedTurnOffset, and zero for formats whose duration cannot be determined.
+func (az *AzureCSTextToSpeech) SynthesizeDialogue(ctx context.Context, turns []DialogueTurn, audioOutput AudioOutput) (*DialogueResult, error) {
+	documents, err := az.dialogueDocuments(turns)
+	if err != nil {
+		return nil, err
+	}
+	for i, turn := range turns {
+		if err := az.validateVoiceStyle(ctx, turn.VoiceParam); err != nil {
+			return nil, fmt.Errorf("turn %d, %w", i, err)
+		}
+	}
+	format, err := audio.ParseFormat(string(audioOutput))
+	if len(documents) > 1 && err != nil {
+		return nil, fmt.Errorf("dialogue exceeds a single request, joining its %d documents requires an uncompressed audio format, got %s", len(documents), audioOutput)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*SynthesisResult, len(documents))
+	var (
+		mu       sync.Mutex
+		firstErr error // the failure which canceled the remaining documents
+	)
+	sem := make(chan struct{}, dialogueConcurrency)
+	var wg sync.WaitGroup
+	for i := range documents {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := az.SynthesizeSSMLResultWithContext(ctx, documents[i].ssml, audioOutput)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to synthesize document %d, %v", i, err)
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	result := &DialogueResult{EstimatedTurns: make([]EstimatedTurnOffset, 0, len(turns))}
+	var (
+		joined []byte
+		offset time.Duration // of the current document
+	)
+	samplesFormat := format // the samples of format, without their WAV header
+	samplesFormat.WAV = false
+	next := 0 // the first turn of the current document
+	for i, d := range documents {
+		result.BillableCharacters += results[i].BillableCharacters
+		documentTurns := turns[next : next+d.turns]
+		next += d.turns
+		offsets, err := az.estimateTurnOffsets(documentTurns, results[i].Duration)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range offsets {
+			o.Offset += offset
+			result.EstimatedTurns = append(result.EstimatedTurns, o)
+		}
+		offset += results[i].Duration
+
+		if len(documents) == 1 {
+			result.Audio = results[i].Audio
+			break
+		}
+		samples, err := audio.Convert(results[i].Audio, format, samplesFormat)
+		if err != nil {
+			return nil, err
+		}
+		joined = append(joined, samples...)
+	}
+	if len(documents) > 1 {
+		if result.Audio, err = audio.Convert(joined, samplesFormat, format); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// estimateTurnOffsets locates the turns of a document lasting duration. The pauses of the turns are exact, the
+// rest of the document is shared among the turns by their billable characters.
+func (az *AzureCSTextToSpeech) estimateTurnOffsets(turns []DialogueTurn, duration time.Duration) ([]EstimatedTurnOffset, error) {
+	offsets := make([]EstimatedTurnOffset, len(turns))
+	if duration <= 0 {
+		return offsets, nil
+	}
+	characters := make([]int, len(turns))
+	total := 0
+	speech := duration
+	for i, turn := range turns {
+		n, err := az.billableCharacters(turn.VoiceParam)
+		if err != nil {
+			return nil, err
+		}
+		characters[i] = n
+		total += n
+		speech -= turn.Pause
+	}
+	speech = max(0, speech)
+
+	var offset time.Duration
+	for i, turn := range turns {
+		var d time.Duration
+		if total > 0 {
+			d = time.Duration(int64(speech) * int64(characters[i]) / int64(total))
+		}
+		offsets[i] = EstimatedTurnOffset{Offset: offset, Duration: d}
+		offset += d + turn.Pause
+	}
+	return offsets, nil
+}
@@ -0,0 +1,130 @@
+package azuretexttospeech
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/WqyJh/azuretexttospeech/audio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialogueSSML(t *testing.T) {
+	az := &AzureCSTextToSpeech{}
+	docs, err := az.DialogueSSML([]DialogueTurn{
+		{VoiceParam: VoiceParam{SpeechText: "Hi!", Voice: "en-US-GuyNeural", Locale: LocaleEnUS, Gender: GenderMale}, Pause: 500 * time.Millisecond},
+		{VoiceParam: VoiceParam{SpeechText: "Hello.", Voice: "en-US-AvaNeural", Locale: LocaleEnUS, Gender: GenderFemale, Style: "cheerful"}},
+	})
+	assert.NoError(t, err)
+	expect := "<speak version='1.0' xmlns='http://www.w3.org/2001/10/synthesis' xmlns:mstts='https://www.w3.org/2001/mstts' xml:lang='en-US'>" +
+		"<voice xml:lang='en-US' xml:gender='Male' name='en-US-GuyNeural'>Hi!<break time='500ms'/></voice>" +
+		"<voice xml:lang='en-US' xml:gender='Female' name='en-US-AvaNeural'><mstts:express-as style='cheerful'>Hello.</mstts:express-as></voice>" +
+		"</speak>"
+	assert.Equal(t, []string{expect}, docs)
+
+	turns := make([]DialogueTurn, maxVoiceElements+1)
+	for i := range turns {
+		turns[i] = DialogueTurn{VoiceParam: VoiceParam{SpeechText: "line", Voice: "en-US-GuyNeural", Locale: LocaleEnUS}}
+	}
+	docs, err = az.DialogueSSML(turns)
+	assert.NoError(t, err)
+	if assert.Len(t, docs, 2, "voice element limit should split the script") {
+		assert.Equal(t, maxVoiceElements, strings.Count(docs[0], "<voice "))
+		assert.Equal(t, 1, strings.Count(docs[1], "<voice "))
+	}
+
+	turns = []DialogueTurn{{VoiceParam: VoiceParam{SpeechText: strings.Repeat("a", maxSSMLSize)}}}
+	_, err = az.DialogueSSML(turns)
+	assert.Error(t, err, "a turn above the size limit cannot be rendered")
+
+	assert.Equal(t, "<break time='20000ms'/><break time='5000ms'/>", breakXML(25*time.Second))
+}
+
+func TestSynthesizeDialogue(t *testing.T) {
+	format, _ := audio.ParseFormat(string(AudioOutput_riff_8khz_16bit_mono_pcm))
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		body, _ := io.ReadAll(r.Body)
+		// 100 ms of audio per voice element and 250 ms per pause.
+		n := bytes.Count(body, []byte("<voice"))*1600 + bytes.Count(body, []byte("<break"))*4000
+		w.Write(audio.EncodeWAV(format, make([]byte, n)))
+	}))
+	defer ts.Close()
+
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL}
+	turns := []DialogueTurn{
+		{VoiceParam: VoiceParam{SpeechText: "Hi!", Voice: "en-US-GuyNeural", Locale: LocaleEnUS}, Pause: 250 * time.Millisecond},
+		{VoiceParam: VoiceParam{SpeechText: "Hello.", Voice: "en-US-AvaNeural", Locale: LocaleEnUS}},
+		{VoiceParam: VoiceParam{SpeechText: "Bye.", Voice: "en-US-GuyNeural", Locale: LocaleEnUS}},
+	}
+	result, err := az.SynthesizeDialogue(context.Background(), turns, AudioOutput_riff_8khz_16bit_mono_pcm)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "the script fits a single document")
+		// the 300 ms of speech are shared by the 3, 6 and 4 characters of the turns.
+		assert.Equal(t, []EstimatedTurnOffset{
+			{Offset: 0, Duration: 69230769},
+			{Offset: 319230769, Duration: 138461538},
+			{Offset: 457692307, Duration: 92307692},
+		}, result.EstimatedTurns)
+		assert.Equal(t, len("Hi!Hello.Bye.<break time='250ms'/>"), result.BillableCharacters, "markup is billable")
+		_, samples, err := audio.ParseWAV(result.Audio)
+		assert.NoError(t, err)
+		assert.Equal(t, 3*1600+4000, len(samples))
+	}
+
+	// a single document is returned in any format.
+	_, err = az.SynthesizeDialogue(context.Background(), turns, AudioOutput_audio_16khz_32kbitrate_mono_mp3)
+	assert.NoError(t, err)
+
+	// scripts above the voice limit are joined from several documents.
+	atomic.StoreInt32(&requests, 0)
+	long := make([]DialogueTurn, maxVoiceElements+1)
+	for i := range long {
+		long[i] = DialogueTurn{VoiceParam: VoiceParam{SpeechText: "Hi!", Voice: "en-US-GuyNeural", Locale: LocaleEnUS}}
+	}
+	result, err = az.SynthesizeDialogue(context.Background(), long, AudioOutput_riff_8khz_16bit_mono_pcm)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+		assert.Len(t, result.EstimatedTurns, len(long))
+		assert.Equal(t, time.Duration(maxVoiceElements)*100*time.Millisecond, result.EstimatedTurns[maxVoiceElements].Offset)
+		_, samples, err := audio.ParseWAV(result.Audio)
+		assert.NoError(t, err)
+		assert.Equal(t, len(long)*1600, len(samples))
+	}
+	_, err = az.SynthesizeDialogue(context.Background(), long, AudioOutput_audio_16khz_32kbitrate_mono_mp3)
+	assert.Error(t, err, "compressed formats cannot be joined")
+}
+
+func TestSynthesizeDialogueValidatesTurns(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/voices" {
+			fmt.Fprintln(w, voiceListAPIStyleResponse)
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer ts.Close()
+
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL, voiceServiceListURL: ts.URL + "/voices", client: &http.Client{}}
+	turns := []DialogueTurn{
+		{VoiceParam: VoiceParam{SpeechText: "Hi!", Voice: "zh-CN-XiaomoNeural", Locale: LocaleZhCN, Style: "cheerful"}},
+		{VoiceParam: VoiceParam{SpeechText: "Hello.", Voice: "zh-CN-XiaomoNeural", Locale: LocaleZhCN, Style: "angry"}},
+	}
+	_, err := az.SynthesizeDialogue(context.Background(), turns, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.ErrorIs(t, err, ErrInvalidRequest, "the style of the second turn is not supported")
+
+	turns[1].Style = ""
+	turns[1].Prosody = &Prosody{Rate: "fast-ish"}
+	_, err = az.SynthesizeDialogue(context.Background(), turns, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests), "invalid turns are never sent")
+}
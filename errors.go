@@ -0,0 +1,60 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ErrInvalidRequest is wrapped by the errors of requests which fail validation before they are sent, such as
+// invalid SSML or a style which the voice does not support.
+var ErrInvalidRequest = errors.New("invalid request")
+
+// invalidRequestError marks an error as wrapping ErrInvalidRequest, keeping its message.
+type invalidRequestError struct {
+	err error
+}
+
+func (e *invalidRequestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidRequestError) Unwrap() []error {
+	return []error{ErrInvalidRequest, e.err}
+}
+
+// invalidRequest returns err marked as wrapping ErrInvalidRequest.
+func invalidRequest(err error) error {
+	return &invalidRequestError{err: err}
+}
+
+// StatusError is returned when the Speech service responds with an unsuccessful HTTP status code.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d - %s", e.StatusCode, e.Message)
+}
+
+// Temporary reports whether the request may succeed when retried, possibly in another region.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode >= http.StatusInternalServerError || e.StatusCode == http.StatusTooManyRequests
+}
+
+// isServiceFailure reports whether err indicates that the Speech service is unavailable, as opposed to a
+// problem of the request.
+func isServiceFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Temporary()
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr)
+}
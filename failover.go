@@ -0,0 +1,374 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Synthesizer is implemented by AzureCSTextToSpeech and FailoverClient.
+type Synthesizer interface {
+	SynthesizeWithContext(ctx context.Context, param VoiceParam, audioOutput AudioOutput) ([]byte, error)
+	SynthesizeResultWithContext(ctx context.Context, param VoiceParam, audioOutput AudioOutput) (*SynthesisResult, error)
+	SynthesizeSSMLWithContext(ctx context.Context, ssml string, audioOutput AudioOutput) ([]byte, error)
+	SynthesizeSSMLResultWithContext(ctx context.Context, ssml string, audioOutput AudioOutput) (*SynthesisResult, error)
+}
+
+var (
+	_ Synthesizer = (*AzureCSTextToSpeech)(nil)
+	_ Synthesizer = (*FailoverClient)(nil)
+)
+
+// Defaults of a FailoverClient.
+const (
+	defaultFailureThreshold = 3
+	defaultProbeInterval    = 30 * time.Second
+	defaultAttemptTimeout   = 20 * time.Second
+	probeTimeout            = 10 * time.Second
+)
+
+// RegionKey is a subscription key of the Speech resource in Region.
+type RegionKey struct {
+	Region          Region
+	SubscriptionKey string
+}
+
+// FailoverOption configures a FailoverClient, see NewFailoverClient.
+type FailoverOption func(*FailoverClient)
+
+// WithClientOptions configures the client of every region with opts.
+func WithClientOptions(opts ...Option) FailoverOption {
+	return func(f *FailoverClient) {
+		f.clientOpts = append(f.clientOpts, opts...)
+	}
+}
+
+// WithCircuitBreakers gives the client of every region its own circuit breaker, returned by newBreaker. An
+// open circuit fails over to the next region immediately.
+func WithCircuitBreakers(newBreaker func(Region) *CircuitBreaker) FailoverOption {
+	return func(f *FailoverClient) {
+		f.newBreaker = newBreaker
+	}
+}
+
+// WithRegionHedging sends the duplicate of a slow request to the next healthy region, as configured by h.
+func WithRegionHedging(h *Hedger) FailoverOption {
+	return func(f *FailoverClient) {
+		f.hedger = h
+	}
+}
+
+// WithFailureThreshold marks a region unhealthy after n consecutive failed requests. The default is 3.
+func WithFailureThreshold(n int) FailoverOption {
+	return func(f *FailoverClient) {
+		f.threshold = n
+	}
+}
+
+// WithProbeInterval sets how often unhealthy regions are probed for recovery. The default is 30 seconds.
+func WithProbeInterval(d time.Duration) FailoverOption {
+	return func(f *FailoverClient) {
+		f.probeInterval = d
+	}
+}
+
+// WithAttemptTimeout bounds each attempt of a request, so that a hanging region fails over before the
+// deadline of the request expires. The default is 20 seconds. Attempts followed by another region never take
+// more than half of the time left before the deadline of the request.
+func WithAttemptTimeout(d time.Duration) FailoverOption {
+	return func(f *FailoverClient) {
+		f.attemptTimeout = d
+	}
+}
+
+// failoverRegion is the client and health state of one region of a FailoverClient.
+type failoverRegion struct {
+	region Region
+	client *AzureCSTextToSpeech
+
+	mu        sync.Mutex
+	failures  int // consecutive failed requests
+	unhealthy bool
+}
+
+// FailoverClient synthesizes speech in the first healthy of several regions. Requests failing with a network
+// error, a timeout or a server error are retried in the next region. A region is skipped as unhealthy after
+// consecutive failures, until a background probe (see Verify) succeeds; the client then fails back to it.
+// Errors caused by the request itself, such as invalid SSML or an exceeded quota, are returned without
+// failover.
+type FailoverClient struct {
+	regions        []*failoverRegion // in order of preference, the first region is the primary
+	clientOpts     []Option
+	newBreaker     func(Region) *CircuitBreaker
+	hedger         *Hedger
+	threshold      int
+	probeInterval  time.Duration
+	attemptTimeout time.Duration
+	done           chan bool
+	closeOnce      sync.Once
+}
+
+// NewFailoverClient returns a client using regions in the given order of preference. A region whose initial
+// token cannot be fetched starts out unhealthy; an error is returned only when no region is usable.
+func NewFailoverClient(regions []RegionKey, opts ...FailoverOption) (*FailoverClient, error) {
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("failover client requires at least one region")
+	}
+	f := &FailoverClient{threshold: defaultFailureThreshold, probeInterval: defaultProbeInterval}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	var errs []string
+	for _, rk := range regions {
+		az := newClient(rk.SubscriptionKey, rk.Region, f.clientOpts...)
+		if f.newBreaker != nil {
+			az.breaker = f.newBreaker(rk.Region)
+		}
+		r := &failoverRegion{region: rk.Region, client: az}
+		if err := az.refreshToken(); err != nil {
+			r.unhealthy = true
+			errs = append(errs, fmt.Sprintf("%s: %v", rk.Region, err))
+		}
+		az.TokenRefreshDoneCh = az.startRefresher()
+		f.regions = append(f.regions, r)
+	}
+	if len(errs) == len(regions) {
+		f.Close()
+		return nil, fmt.Errorf("failed to fetch initial token in any region, %s", strings.Join(errs, "; "))
+	}
+	f.start()
+	return f, nil
+}
+
+// start runs the probes of unhealthy regions until Close is called.
+func (f *FailoverClient) start() {
+	f.done = make(chan bool, 1)
+	go func() {
+		ticker := time.NewTicker(f.probeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				f.probe()
+			case <-f.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background probes and token refreshes. Calls after the first do nothing.
+func (f *FailoverClient) Close() {
+	f.closeOnce.Do(func() {
+		if f.done != nil {
+			f.done <- true
+		}
+		for _, r := range f.regions {
+			if r.client.TokenRefreshDoneCh != nil {
+				r.client.TokenRefreshDoneCh <- true
+			}
+		}
+	})
+}
+
+// Regions returns the regions considered healthy, in order of preference.
+func (f *FailoverClient) Regions() []Region {
+	var healthy []Region
+	for _, r := range f.regions {
+		if r.healthy() {
+			healthy = append(healthy, r.region)
+		}
+	}
+	return healthy
+}
+
+// probe verifies the unhealthy regions, marking those which pass as healthy.
+func (f *FailoverClient) probe() {
+	for _, r := range f.regions {
+		if r.healthy() {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		err := r.client.Verify(ctx).Err()
+		cancel()
+		if err != nil {
+			r.client.logger().LogAttrs(ctx, slog.LevelDebug, "region is still unhealthy",
+				slog.String("region", string(r.region)), slog.Any("error", err))
+			continue
+		}
+		r.mu.Lock()
+		r.unhealthy = false
+		r.failures = 0
+		r.mu.Unlock()
+		r.client.logger().LogAttrs(ctx, slog.LevelInfo, "region recovered", slog.String("region", string(r.region)))
+	}
+}
+
+func (r *failoverRegion) healthy() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.unhealthy
+}
+
+// record updates the health of the region after a request, err is nil for a successful request.
+func (r *failoverRegion) record(err error, threshold int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		r.failures = 0
+		r.unhealthy = false
+		return
+	}
+	r.failures++
+	if r.failures >= threshold && !r.unhealthy {
+		r.unhealthy = true
+		r.client.logger().LogAttrs(context.Background(), slog.LevelWarn, "region marked unhealthy",
+			slog.String("region", string(r.region)), slog.Int("failures", r.failures), slog.Any("error", err))
+	}
+}
+
+// candidates returns the healthy regions in order of preference, or all regions when none is healthy.
+func (f *FailoverClient) candidates() []*failoverRegion {
+	var healthy []*failoverRegion
+	for _, r := range f.regions {
+		if r.healthy() {
+			healthy = append(healthy, r)
+		}
+	}
+	if len(healthy) == 0 {
+		return f.regions
+	}
+	return healthy
+}
+
+// shouldFailover reports whether err, returned by an attempt of a request with context ctx, may not occur in
+// another region.
+func shouldFailover(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		// the caller canceled the request or its deadline expired.
+		return false
+	}
+	return errors.Is(err, ErrCircuitOpen) || isServiceFailure(err)
+}
+
+// attemptDuration returns the timeout of an attempt of a request with context ctx. Unless it is the last
+// attempt, it leaves at least half of the time before the deadline of the request to the next region.
+func (f *FailoverClient) attemptDuration(ctx context.Context, last bool) time.Duration {
+	d := f.attemptTimeout
+	if d <= 0 {
+		d = defaultAttemptTimeout
+	}
+	if deadline, ok := ctx.Deadline(); ok && !last {
+		if share := time.Until(deadline) / 2; share < d {
+			d = share
+		}
+	}
+	return d
+}
+
+// do runs synthesize against the candidate regions until it succeeds or fails with an error that failover
+// cannot fix. With a Hedger, the second candidate receives the hedge of the request to the first; characters
+// are the billable characters of the request, negative to disable hedging.
+func (f *FailoverClient) do(ctx context.Context, characters int, synthesize func(context.Context, *AzureCSTextToSpeech) (*SynthesisResult, error)) (*SynthesisResult, error) {
+	ctx = withAttempts(ctx)
+	var (
+		mu   sync.Mutex
+		errs []string
+	)
+	attempt := func(r *failoverRegion, last bool) func(context.Context) (*SynthesisResult, error) {
+		return func(attemptCtx context.Context) (*SynthesisResult, error) {
+			attemptCtx, cancel := context.WithTimeout(attemptCtx, f.attemptDuration(ctx, last))
+			result, err := synthesize(attemptCtx, r.client)
+			timedOut := errors.Is(attemptCtx.Err(), context.DeadlineExceeded)
+			cancel()
+			if err == nil {
+				r.record(nil, f.threshold)
+				return result, nil
+			}
+			if !shouldFailover(ctx, err) {
+				if timedOut {
+					// the region did not answer before the deadline of the request.
+					r.record(err, f.threshold)
+				}
+				return nil, err
+			}
+			r.record(err, f.threshold)
+			r.client.logger().LogAttrs(ctx, slog.LevelWarn, "synthesis failed, trying next region",
+				slog.String("region", string(r.region)), slog.Any("error", err))
+			mu.Lock()
+			errs = append(errs, fmt.Sprintf("%s: %v", r.region, err))
+			mu.Unlock()
+			return nil, err
+		}
+	}
+
+	candidates := f.candidates()
+	if f.hedger != nil && characters >= 0 && len(candidates) > 1 {
+		hedged := false
+		hedge := attempt(candidates[1], len(candidates) == 2)
+		result, err := f.hedger.run(ctx, characters, attempt(candidates[0], false), func(ctx context.Context) (*SynthesisResult, error) {
+			hedged = true
+			return hedge(ctx)
+		})
+		if err == nil || !shouldFailover(ctx, err) {
+			return result, err
+		}
+		// run fails only after both requests finished, so reading hedged does not race.
+		candidates = candidates[1:]
+		if hedged {
+			candidates = candidates[1:]
+		}
+	}
+	for i, r := range candidates {
+		result, err := attempt(r, i == len(candidates)-1)(ctx)
+		if err == nil || !shouldFailover(ctx, err) {
+			return result, err
+		}
+	}
+	return nil, fmt.Errorf("synthesis failed in all regions, %s", strings.Join(errs, "; "))
+}
+
+// SynthesizeWithContext implements Synthesizer.
+func (f *FailoverClient) SynthesizeWithContext(ctx context.Context, param VoiceParam, audioOutput AudioOutput) ([]byte, error) {
+	result, err := f.SynthesizeResultWithContext(ctx, param, audioOutput)
+	if err != nil {
+		return nil, err
+	}
+	return result.Audio, nil
+}
+
+// SynthesizeResultWithContext implements Synthesizer.
+func (f *FailoverClient) SynthesizeResultWithContext(ctx context.Context, param VoiceParam, audioOutput AudioOutput) (*SynthesisResult, error) {
+	characters, err := f.regions[0].client.billableCharacters(param)
+	if err != nil {
+		characters = -1
+	}
+	return f.do(ctx, characters, func(ctx context.Context, az *AzureCSTextToSpeech) (*SynthesisResult, error) {
+		return az.SynthesizeResultWithContext(ctx, param, audioOutput)
+	})
+}
+
+// SynthesizeSSMLWithContext implements Synthesizer.
+func (f *FailoverClient) SynthesizeSSMLWithContext(ctx context.Context, ssml string, audioOutput AudioOutput) ([]byte, error) {
+	result, err := f.SynthesizeSSMLResultWithContext(ctx, ssml, audioOutput)
+	if err != nil {
+		return nil, err
+	}
+	return result.Audio, nil
+}
+
+// SynthesizeSSMLResultWithContext implements Synthesizer.
+func (f *FailoverClient) SynthesizeSSMLResultWithContext(ctx context.Context, ssml string, audioOutput AudioOutput) (*SynthesisResult, error) {
+	characters, err := BillableCharacters(ssml)
+	if err != nil {
+		characters = -1
+	}
+	return f.do(ctx, characters, func(ctx context.Context, az *AzureCSTextToSpeech) (*SynthesisResult, error) {
+		return az.SynthesizeSSMLResultWithContext(ctx, ssml, audioOutput)
+	})
+}
@@ -0,0 +1,197 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestRegion returns a region served by a test server answering synthesis requests with status.
+func newTestRegion(t *testing.T, region Region, status *atomic.Int32) (*failoverRegion, *atomic.Int32) {
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("SYS49152"))
+	})
+	mux.HandleFunc("/voices", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, voiceListAPIGoodResponse)
+	})
+	mux.HandleFunc("/tts", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		w.Write([]byte(region))
+	})
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	return &failoverRegion{region: region, client: &AzureCSTextToSpeech{
+		accessToken:         "SYS49152",
+		tokenRefreshURL:     ts.URL + "/token",
+		voiceServiceListURL: ts.URL + "/voices",
+		textToSpeechURL:     ts.URL + "/tts",
+		client:              &http.Client{},
+	}}, &requests
+}
+
+func TestFailoverClient(t *testing.T) {
+	var eastStatus, westStatus atomic.Int32
+	eastStatus.Store(http.StatusServiceUnavailable)
+	westStatus.Store(http.StatusOK)
+	east, eastRequests := newTestRegion(t, RegionEastUS, &eastStatus)
+	west, _ := newTestRegion(t, RegionWestUS, &westStatus)
+	f := &FailoverClient{regions: []*failoverRegion{east, west}, threshold: 2}
+
+	ctx := context.Background()
+	param := VoiceParam{SpeechText: "Hello", Voice: "ar-EG-Hoda", Locale: LocaleEnUS}
+	for i := 0; i < 3; i++ {
+		audio, err := f.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(RegionWestUS), audio)
+	}
+	assert.Equal(t, int32(2), eastRequests.Load(), "the primary is skipped once unhealthy")
+	assert.Equal(t, []Region{RegionWestUS}, f.Regions())
+
+	// requests failing for reasons other than the region are not retried.
+	westStatus.Store(http.StatusBadRequest)
+	_, err := f.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+
+	// the probe fails back to the recovered primary.
+	eastStatus.Store(http.StatusOK)
+	f.probe()
+	assert.Equal(t, []Region{RegionEastUS, RegionWestUS}, f.Regions())
+	audio, err := f.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(RegionEastUS), audio)
+}
+
+func TestFailoverAttempts(t *testing.T) {
+	var eastStatus, westStatus atomic.Int32
+	eastStatus.Store(http.StatusServiceUnavailable)
+	westStatus.Store(http.StatusOK)
+	east, _ := newTestRegion(t, RegionEastUS, &eastStatus)
+	west, _ := newTestRegion(t, RegionWestUS, &westStatus)
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	WithTracerProvider(tp)(east.client)
+	WithTracerProvider(tp)(west.client)
+	f := &FailoverClient{regions: []*failoverRegion{east, west}, threshold: 2}
+
+	_, err := f.SynthesizeWithContext(context.Background(), VoiceParam{SpeechText: "Hello", Voice: "ar-EG-Hoda", Locale: LocaleEnUS}, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.NoError(t, err)
+	var attempts []int64
+	for _, span := range spans.Ended() {
+		attrs := attribute.NewSet(span.Attributes()...)
+		v, _ := attrs.Value(attrAttempt)
+		attempts = append(attempts, v.AsInt64())
+	}
+	assert.Equal(t, []int64{1, 2}, attempts, "the attempts of a request are numbered across regions")
+}
+
+func TestFailoverAllRegionsFail(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusBadGateway)
+	east, _ := newTestRegion(t, RegionEastUS, &status)
+	west, _ := newTestRegion(t, RegionWestUS, &status)
+	f := &FailoverClient{regions: []*failoverRegion{east, west}, threshold: 1, attemptTimeout: time.Second}
+
+	_, err := f.SynthesizeWithContext(context.Background(), VoiceParam{SpeechText: "Hello", Voice: "ar-EG-Hoda", Locale: LocaleEnUS}, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.ErrorContains(t, err, "synthesis failed in all regions")
+	assert.Empty(t, f.Regions())
+
+	// with no healthy region every region is still tried, a success marks the region healthy again.
+	status.Store(http.StatusOK)
+	_, err = f.SynthesizeWithContext(context.Background(), VoiceParam{SpeechText: "Hello", Voice: "ar-EG-Hoda", Locale: LocaleEnUS}, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.NoError(t, err)
+	assert.Equal(t, []Region{RegionEastUS}, f.Regions())
+}
+
+func TestFailoverHealth(t *testing.T) {
+	var eastStatus, westStatus atomic.Int32
+	eastStatus.Store(http.StatusServiceUnavailable)
+	westStatus.Store(http.StatusOK)
+	east, _ := newTestRegion(t, RegionEastUS, &eastStatus)
+	west, _ := newTestRegion(t, RegionWestUS, &westStatus)
+	f := &FailoverClient{regions: []*failoverRegion{east, west}, threshold: 2}
+	ctx := context.Background()
+	param := VoiceParam{SpeechText: "Hello", Voice: "ar-EG-Hoda", Locale: LocaleEnUS}
+
+	_, err := f.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.NoError(t, err)
+	// an error caused by the request is no sign of health.
+	eastStatus.Store(http.StatusBadRequest)
+	_, err = f.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.Error(t, err)
+	eastStatus.Store(http.StatusServiceUnavailable)
+	_, err = f.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.NoError(t, err)
+	assert.Equal(t, []Region{RegionWestUS}, f.Regions(), "the failures before and after the bad request add up")
+}
+
+func TestFailoverAttemptTimeout(t *testing.T) {
+	hang := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-hang:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(hang)
+	var westStatus atomic.Int32
+	westStatus.Store(http.StatusOK)
+	west, _ := newTestRegion(t, RegionWestUS, &westStatus)
+	east := &failoverRegion{region: RegionEastUS, client: &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: slow.URL, client: &http.Client{}}}
+	f := &FailoverClient{regions: []*failoverRegion{east, west}, threshold: 1}
+	param := VoiceParam{SpeechText: "Hello", Voice: "ar-EG-Hoda", Locale: LocaleEnUS}
+
+	// without an attempt timeout, the hanging region still leaves half of the deadline to the next one.
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	audio, err := f.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(RegionWestUS), audio)
+	assert.Equal(t, []Region{RegionWestUS}, f.Regions(), "a timed out attempt is a failure")
+
+	// the last region failing at the deadline of the request is a failure too.
+	f = &FailoverClient{regions: []*failoverRegion{{region: RegionEastUS, client: east.client}}, threshold: 1}
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = f.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, f.Regions())
+}
+
+func TestFailoverClose(t *testing.T) {
+	region := &failoverRegion{region: RegionEastUS, client: &AzureCSTextToSpeech{}}
+	region.client.TokenRefreshDoneCh = region.client.startRefresher()
+	f := &FailoverClient{regions: []*failoverRegion{region}, probeInterval: time.Hour}
+	f.start()
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			f.Close()
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("repeated calls to Close block")
+	}
+}
@@ -0,0 +1,86 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Names of the checks run by Verify. Voice checks are named "voice:" followed by the voice name.
+const (
+	CheckToken     = "token"
+	CheckVoiceList = "voice_list"
+)
+
+// CheckResult is the outcome of one check run by Verify.
+type CheckResult struct {
+	Name     string        `json:"name"`
+	OK       bool          `json:"ok"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// VerifyReport is the outcome of Verify. It encodes to JSON for readiness endpoints.
+type VerifyReport struct {
+	OK     bool          `json:"ok"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Err returns an error listing the failed checks, or nil when all checks passed.
+func (r *VerifyReport) Err() error {
+	var failed []string
+	for _, c := range r.Checks {
+		if !c.OK {
+			failed = append(failed, fmt.Sprintf("%s: %s", c.Name, c.Error))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("verification failed, %s", strings.Join(failed, "; "))
+}
+
+// Verify checks that the subscription key and region are usable without synthesizing billable audio: it issues
+// a new access token, fetches the voice list of the region and checks that each of voices is listed. Every
+// check is run and reported, so a single call shows all problems. Voices match the short or the full voice
+// name. The fetched voice list also replaces the one cached for style validation.
+func (az *AzureCSTextToSpeech) Verify(ctx context.Context, voices ...string) *VerifyReport {
+	report := &VerifyReport{OK: true}
+	check := func(name string, start time.Time, err error) {
+		result := CheckResult{Name: name, OK: err == nil, Duration: time.Since(start)}
+		if err != nil {
+			result.Error = err.Error()
+			report.OK = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	start := time.Now()
+	check(CheckToken, start, az.refreshTokenWithContext(ctx))
+
+	start = time.Now()
+	list, err := az.fetchVoiceList(ctx)
+	check(CheckVoiceList, start, err)
+	if err == nil {
+		az.voicesMu.Lock()
+		az.voices = list
+		az.voicesMu.Unlock()
+	}
+
+	for _, voice := range voices {
+		start = time.Now()
+		err := fmt.Errorf("voice list is unavailable")
+		if list != nil {
+			err = fmt.Errorf("voice %s is not available in this region", voice)
+			for _, v := range list {
+				if v.ShortName == voice || v.Name == voice {
+					err = nil
+					break
+				}
+			}
+		}
+		check("voice:"+voice, start, err)
+	}
+	return report
+}
@@ -0,0 +1,102 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	validKey := true
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if !validKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("SYS49152"))
+	})
+	mux.HandleFunc("/voices", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer SYS49152" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintln(w, voiceListAPIGoodResponse)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	az := &AzureCSTextToSpeech{
+		SubscriptionKey:     "SYS64738",
+		tokenRefreshURL:     ts.URL + "/token",
+		voiceServiceListURL: ts.URL + "/voices",
+		client:              &http.Client{},
+	}
+	report := az.Verify(context.Background(), "ar-EG-Hoda", "xx-XX-Nobody")
+	assert.False(t, report.OK)
+	if assert.Len(t, report.Checks, 4) {
+		assert.Equal(t, CheckToken, report.Checks[0].Name)
+		assert.True(t, report.Checks[0].OK)
+		assert.True(t, report.Checks[1].OK)
+		assert.True(t, report.Checks[2].OK)
+		assert.Equal(t, "voice:xx-XX-Nobody", report.Checks[3].Name)
+		assert.False(t, report.Checks[3].OK)
+	}
+	assert.EqualError(t, report.Err(), "verification failed, voice:xx-XX-Nobody: voice xx-XX-Nobody is not available in this region")
+	assert.NotNil(t, az.voices, "voice list is cached")
+
+	report = az.Verify(context.Background(), "ar-EG-Hoda")
+	assert.True(t, report.OK)
+	assert.NoError(t, report.Err())
+
+	validKey = false
+	az.accessToken = ""
+	report = az.Verify(context.Background(), "ar-EG-Hoda")
+	assert.False(t, report.OK)
+	for _, c := range report.Checks {
+		assert.False(t, c.OK, c.Name)
+		assert.NotEmpty(t, c.Error, c.Name)
+	}
+}
+
+func TestVerifyConcurrentSynthesis(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("SYS49152"))
+	})
+	mux.HandleFunc("/voices", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, voiceListAPIGoodResponse)
+	})
+	mux.HandleFunc("/tts", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("SYS4096"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	az := &AzureCSTextToSpeech{
+		tokenRefreshURL:     ts.URL + "/token",
+		voiceServiceListURL: ts.URL + "/voices",
+		textToSpeechURL:     ts.URL + "/tts",
+		client:              &http.Client{},
+	}
+	// run with -race: Verify replaces the token while requests read it.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			az.Verify(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := az.SynthesizeWithContext(context.Background(), VoiceParam{SpeechText: "hi", Voice: "ar-EG-Hoda", Locale: LocaleEnUS, Gender: GenderFemale}, AudioOutput_riff_8khz_8bit_mono_alaw)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,131 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Defaults of a Hedger.
+const (
+	defaultHedgeDelay      = 300 * time.Millisecond
+	defaultHedgeSpendRatio = 0.1
+	defaultHedgeMaxBudget  = 10000
+)
+
+type headersKey struct{}
+
+// withHeadersCallback returns a copy of ctx on which post calls f once the response headers arrived.
+func withHeadersCallback(ctx context.Context, f func()) context.Context {
+	return context.WithValue(ctx, headersKey{}, f)
+}
+
+// gotHeaders calls the callback set on ctx with withHeadersCallback, if any.
+func gotHeaders(ctx context.Context) {
+	if f, ok := ctx.Value(headersKey{}).(func()); ok {
+		f()
+	}
+}
+
+type sentKey struct{}
+
+// withSentCallback returns a copy of ctx on which post calls f once the request was written.
+func withSentCallback(ctx context.Context, f func()) context.Context {
+	return context.WithValue(ctx, sentKey{}, f)
+}
+
+// requestSent calls the callback set on ctx with withSentCallback, if any.
+func requestSent(ctx context.Context) {
+	if f, ok := ctx.Value(sentKey{}).(func()); ok {
+		f()
+	}
+}
+
+// Hedger reduces the tail latency of synthesis: when the response headers of a request have not arrived
+// after Delay, a duplicate request is sent and the first successful response wins, canceling the other.
+// See WithHedging and WithRegionHedging.
+//
+// Both requests of a hedge may be billed. To cap the extra spend, every request earns SpendRatio times its
+// billable characters of hedging budget and a hedge spends its characters, so hedges never bill more than
+// SpendRatio of the characters of all requests. The budget saved up during quiet periods is capped at
+// MaxBudget characters, so that it cannot pay for a burst of hedges later. Zero fields use the defaults given
+// in their comments.
+type Hedger struct {
+	Delay      time.Duration // default 300 milliseconds
+	SpendRatio float64       // default 0.1
+	MaxBudget  int           // default 10000 characters
+
+	mu     sync.Mutex
+	budget float64 // characters available for hedges
+}
+
+// deposit adds the budget earned by a request of characters.
+func (h *Hedger) deposit(characters int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.budget += orDefault(h.SpendRatio, defaultHedgeSpendRatio) * float64(characters)
+	h.budget = min(h.budget, float64(orDefault(h.MaxBudget, defaultHedgeMaxBudget)))
+}
+
+// withdraw reports whether a hedge of characters fits the budget, spending it if so.
+func (h *Hedger) withdraw(characters int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.budget < float64(characters) {
+		return false
+	}
+	h.budget -= float64(characters)
+	return true
+}
+
+// run calls primary and, when its response headers did not arrive within Delay and the budget allows, hedge.
+// It returns the first successful result, or the error of primary when both fail.
+func (h *Hedger) run(ctx context.Context, characters int, primary, hedge func(context.Context) (*SynthesisResult, error)) (*SynthesisResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		result *SynthesisResult
+		err    error
+		hedged bool
+	}
+	outcomes := make(chan outcome, 2)
+	headers := make(chan struct{})
+	var once sync.Once
+	primaryCtx := withHeadersCallback(ctx, func() { once.Do(func() { close(headers) }) })
+	go func() {
+		result, err := primary(primaryCtx)
+		outcomes <- outcome{result: result, err: err}
+	}()
+	h.deposit(characters)
+
+	timer := time.NewTimer(orDefault(h.Delay, defaultHedgeDelay))
+	defer timer.Stop()
+	pending := 1
+	select {
+	case <-headers:
+	case o := <-outcomes:
+		return o.result, o.err
+	case <-timer.C:
+		if h.withdraw(characters) {
+			pending++
+			go func() {
+				result, err := hedge(ctx)
+				outcomes <- outcome{result: result, err: err, hedged: true}
+			}()
+		}
+	}
+
+	var primaryErr error
+	for ; pending > 0; pending-- {
+		o := <-outcomes
+		if o.err == nil {
+			o.result.Hedged = o.hedged
+			return o.result, nil
+		}
+		if !o.hedged {
+			primaryErr = o.err
+		}
+	}
+	return nil, primaryErr
+}
@@ -0,0 +1,105 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHedgerRun(t *testing.T) {
+	slow := func(ctx context.Context) (*SynthesisResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	fast := func(ctx context.Context) (*SynthesisResult, error) {
+		return &SynthesisResult{Audio: []byte("hedge")}, nil
+	}
+	ctx := context.Background()
+
+	h := &Hedger{Delay: 10 * time.Millisecond, SpendRatio: 0.5}
+	h.deposit(10)
+	result, err := h.run(ctx, 10, slow, fast)
+	assert.NoError(t, err)
+	assert.True(t, result.Hedged)
+
+	// the budget earned by both requests does not cover another hedge.
+	ctx2, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = h.run(ctx2, 10, slow, fast)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// a primary which received its response headers is not hedged.
+	h = &Hedger{Delay: 10 * time.Millisecond, SpendRatio: 1}
+	headersFirst := func(ctx context.Context) (*SynthesisResult, error) {
+		gotHeaders(ctx)
+		time.Sleep(30 * time.Millisecond)
+		return &SynthesisResult{Audio: []byte("primary")}, nil
+	}
+	result, err = h.run(ctx, 10, headersFirst, fast)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("primary"), result.Audio)
+	assert.False(t, result.Hedged)
+
+	// the budget saved up does not exceed MaxBudget.
+	h = &Hedger{SpendRatio: 1, MaxBudget: 15}
+	h.deposit(100)
+	assert.True(t, h.withdraw(10))
+	assert.False(t, h.withdraw(10))
+}
+
+func TestSynthesizeHedging(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			// the server notices the client canceling only after reading the request.
+			io.ReadAll(r.Body)
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("SYS4096"))
+	}))
+	defer ts.Close()
+
+	var metered atomic.Int32
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL}
+	WithHedging(&Hedger{Delay: 20 * time.Millisecond, SpendRatio: 1})(az)
+	WithUsageMeter(UsageMeterFunc(func(ctx context.Context, u Usage) {
+		metered.Add(int32(u.Characters))
+	}))(az)
+	result, err := az.SynthesizeResultWithContext(context.Background(), VoiceParam{SpeechText: "Hello", Voice: "ar-EG-Hoda", Locale: LocaleEnUS}, AudioOutput_riff_8khz_16bit_mono_pcm)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte("SYS4096"), result.Audio)
+		assert.True(t, result.Hedged)
+	}
+	assert.Equal(t, int32(2), requests.Load())
+	// the abandoned request is metered once it returns, which may be after the result.
+	assert.Eventually(t, func() bool { return metered.Load() == 10 }, time.Second, 5*time.Millisecond, "both requests may be billed")
+}
+
+func TestFailoverHedging(t *testing.T) {
+	var eastStatus, westStatus atomic.Int32
+	eastStatus.Store(http.StatusOK)
+	westStatus.Store(http.StatusOK)
+	east, _ := newTestRegion(t, RegionEastUS, &eastStatus)
+	west, _ := newTestRegion(t, RegionWestUS, &westStatus)
+	slowEast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		<-r.Context().Done()
+	}))
+	defer slowEast.Close()
+	east.client.textToSpeechURL = slowEast.URL
+
+	f := &FailoverClient{regions: []*failoverRegion{east, west}, threshold: 1, hedger: &Hedger{Delay: 20 * time.Millisecond, SpendRatio: 1}}
+	result, err := f.SynthesizeResultWithContext(context.Background(), VoiceParam{SpeechText: "Hello", Voice: "ar-EG-Hoda", Locale: LocaleEnUS}, AudioOutput_riff_8khz_16bit_mono_pcm)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte(RegionWestUS), result.Audio)
+		assert.True(t, result.Hedged)
+	}
+	assert.Equal(t, []Region{RegionEastUS, RegionWestUS}, f.Regions(), "the canceled request does not count as a failure")
+}
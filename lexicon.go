@@ -0,0 +1,144 @@
+package azuretexttospeech
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// lexiconMaxSize is the largest custom lexicon file the Speech service accepts.
+// See: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/speech-synthesis-markup-pronunciation#custom-lexicon
+const lexiconMaxSize = 100 * 1024
+
+// plsNamespace is the XML namespace of the W3C Pronunciation Lexicon Specification.
+const plsNamespace = "http://www.w3.org/2005/01/pronunciation-lexicon"
+
+// PhoneticAlphabet is the alphabet used to spell out phonemes in a lexicon or a `<phoneme>` element.
+// See: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/speech-ssml-phonetic-sets
+type PhoneticAlphabet string
+
+const (
+	AlphabetIPA  PhoneticAlphabet = "ipa"             // International Phonetic Alphabet
+	AlphabetSAPI PhoneticAlphabet = "sapi"            // Speech API phone set
+	AlphabetUPS  PhoneticAlphabet = "x-microsoft-ups" // Universal Phone Set
+)
+
+// IsValid reports whether a is one of the alphabets supported by the Speech service.
+func (a PhoneticAlphabet) IsValid() bool {
+	switch a {
+	case AlphabetIPA, AlphabetSAPI, AlphabetUPS:
+		return true
+	}
+	return false
+}
+
+// Lexeme is a single entry of a pronunciation lexicon. Each grapheme (the written form) is pronounced
+// using the phonemes or replaced by the aliases listed for the entry.
+type Lexeme struct {
+	Graphemes []string
+	Phonemes  []string
+	Aliases   []string
+}
+
+// Lexicon builds a W3C PLS document which can be hosted and referenced from SSML through
+// `VoiceParam.LexiconURIs`.
+// See: https://www.w3.org/TR/pronunciation-lexicon/
+type Lexicon struct {
+	Locale   Locale
+	Alphabet PhoneticAlphabet
+	Lexemes  []Lexeme
+}
+
+// NewLexicon returns an empty Lexicon for the given locale and phonetic alphabet.
+func NewLexicon(locale Locale, alphabet PhoneticAlphabet) *Lexicon {
+	return &Lexicon{Locale: locale, Alphabet: alphabet}
+}
+
+// AddPhoneme adds an entry pronouncing grapheme using phoneme, spelled in the lexicon's alphabet.
+func (l *Lexicon) AddPhoneme(grapheme, phoneme string) *Lexicon {
+	return l.Add(Lexeme{Graphemes: []string{grapheme}, Phonemes: []string{phoneme}})
+}
+
+// AddAlias adds an entry which replaces grapheme with alias before it is spoken.
+func (l *Lexicon) AddAlias(grapheme, alias string) *Lexicon {
+	return l.Add(Lexeme{Graphemes: []string{grapheme}, Aliases: []string{alias}})
+}
+
+// Add appends a lexeme to the lexicon.
+func (l *Lexicon) Add(lexeme Lexeme) *Lexicon {
+	l.Lexemes = append(l.Lexemes, lexeme)
+	return l
+}
+
+// Validate checks the lexicon for errors that would cause the Speech service to reject it.
+func (l *Lexicon) Validate() error {
+	if l.Locale == "" {
+		return fmt.Errorf("lexicon locale is required")
+	}
+	if !l.Alphabet.IsValid() {
+		return fmt.Errorf("unsupported phonetic alphabet %q", l.Alphabet)
+	}
+	if len(l.Lexemes) == 0 {
+		return fmt.Errorf("lexicon has no entries")
+	}
+	for i, lx := range l.Lexemes {
+		if len(lx.Graphemes) == 0 {
+			return fmt.Errorf("lexeme %d has no grapheme", i)
+		}
+		if len(lx.Phonemes) == 0 && len(lx.Aliases) == 0 {
+			return fmt.Errorf("lexeme %d (%s) requires a phoneme or an alias", i, lx.Graphemes[0])
+		}
+		for _, values := range [][]string{lx.Graphemes, lx.Phonemes, lx.Aliases} {
+			for _, v := range values {
+				if strings.TrimSpace(v) == "" {
+					return fmt.Errorf("lexeme %d contains an empty value", i)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type plsLexicon struct {
+	XMLName  xml.Name    `xml:"lexicon"`
+	Version  string      `xml:"version,attr"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Alphabet string      `xml:"alphabet,attr"`
+	Lang     string      `xml:"xml:lang,attr"`
+	Lexemes  []plsLexeme `xml:"lexeme"`
+}
+
+type plsLexeme struct {
+	Graphemes []string `xml:"grapheme"`
+	Phonemes  []string `xml:"phoneme"`
+	Aliases   []string `xml:"alias"`
+}
+
+// Marshal validates the lexicon and renders it as a PLS XML document.
+func (l *Lexicon) Marshal() ([]byte, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	doc := plsLexicon{
+		Version:  "1.0",
+		Xmlns:    plsNamespace,
+		Alphabet: string(l.Alphabet),
+		Lang:     string(l.Locale),
+	}
+	for _, lx := range l.Lexemes {
+		doc.Lexemes = append(doc.Lexemes, plsLexeme(lx))
+	}
+
+	var result bytes.Buffer
+	result.WriteString(xml.Header)
+	enc := xml.NewEncoder(&result)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode lexicon, %v", err)
+	}
+	if result.Len() > lexiconMaxSize {
+		return nil, fmt.Errorf("lexicon is %d bytes, exceeding the %d byte limit", result.Len(), lexiconMaxSize)
+	}
+	return result.Bytes(), nil
+}
@@ -0,0 +1,63 @@
+package azuretexttospeech
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLexiconMarshal(t *testing.T) {
+	l := NewLexicon(LocaleEnUS, AlphabetIPA).
+		AddAlias("BTW", "By the way").
+		AddPhoneme("Benigni", "bɛˈniːnji").
+		Add(Lexeme{Graphemes: []string{"Contoso", "contoso"}, Phonemes: []string{"ˈkɒn.təʊ.səʊ"}})
+
+	b, err := l.Marshal()
+	assert.NoError(t, err)
+	expect := `<?xml version="1.0" encoding="UTF-8"?>
+<lexicon version="1.0" xmlns="http://www.w3.org/2005/01/pronunciation-lexicon" alphabet="ipa" xml:lang="en-US">
+  <lexeme>
+    <grapheme>BTW</grapheme>
+    <alias>By the way</alias>
+  </lexeme>
+  <lexeme>
+    <grapheme>Benigni</grapheme>
+    <phoneme>bɛˈniːnji</phoneme>
+  </lexeme>
+  <lexeme>
+    <grapheme>Contoso</grapheme>
+    <grapheme>contoso</grapheme>
+    <phoneme>ˈkɒn.təʊ.səʊ</phoneme>
+  </lexeme>
+</lexicon>`
+	assert.Equal(t, expect, string(b))
+}
+
+func TestLexiconValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		lexicon *Lexicon
+		err     string
+	}{
+		{"missing locale", NewLexicon("", AlphabetIPA).AddAlias("a", "b"), "locale"},
+		{"bad alphabet", NewLexicon(LocaleEnUS, "klingon").AddAlias("a", "b"), "alphabet"},
+		{"no entries", NewLexicon(LocaleEnUS, AlphabetSAPI), "no entries"},
+		{"no grapheme", NewLexicon(LocaleEnUS, AlphabetUPS).Add(Lexeme{Aliases: []string{"b"}}), "no grapheme"},
+		{"no pronunciation", NewLexicon(LocaleEnUS, AlphabetIPA).Add(Lexeme{Graphemes: []string{"a"}}), "phoneme or an alias"},
+		{"empty value", NewLexicon(LocaleEnUS, AlphabetIPA).AddPhoneme("a", " "), "empty value"},
+	}
+	for _, tt := range tests {
+		err := tt.lexicon.Validate()
+		if assert.Error(t, err, tt.name) {
+			assert.Contains(t, err.Error(), tt.err, tt.name)
+		}
+	}
+
+	big := NewLexicon(LocaleEnUS, AlphabetIPA)
+	for i := 0; i < 2000; i++ {
+		big.AddAlias(strings.Repeat("x", 40), strings.Repeat("y", 40))
+	}
+	_, err := big.Marshal()
+	assert.Error(t, err, "lexicon larger than the service limit should be rejected")
+}
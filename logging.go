@@ -0,0 +1,71 @@
+package azuretexttospeech
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// redactedHeaders are request headers carrying credentials, which are never logged.
+var redactedHeaders = map[string]bool{
+	"Authorization":             true,
+	"Ocp-Apim-Subscription-Key": true,
+}
+
+// logger returns the logger of the client, slog.Default() unless set with WithLogger.
+func (az *AzureCSTextToSpeech) logger() *slog.Logger {
+	if az.log == nil {
+		return slog.Default()
+	}
+	return az.log
+}
+
+// headerAttrs returns h as a log attribute with credentials redacted.
+func headerAttrs(h http.Header) slog.Attr {
+	attrs := make([]slog.Attr, 0, len(h))
+	for name, values := range h {
+		value := ""
+		if len(values) > 0 {
+			value = values[0]
+		}
+		if redactedHeaders[name] {
+			value = "REDACTED"
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.Attr{Key: "headers", Value: slog.GroupValue(attrs...)}
+}
+
+// do sends request with the client of az, logging the request and response metadata, along with attrs, at debug
+// level.
+func (az *AzureCSTextToSpeech) do(request *http.Request, attrs ...slog.Attr) (*http.Response, error) {
+	ctx := request.Context()
+	logger := az.logger()
+	debug := logger.Enabled(ctx, slog.LevelDebug)
+	if debug {
+		logger.LogAttrs(ctx, slog.LevelDebug, "sending request", append([]slog.Attr{
+			slog.String("method", request.Method),
+			slog.String("url", request.URL.String()),
+			headerAttrs(request.Header)}, attrs...)...)
+	}
+
+	start := time.Now()
+	response, err := az.httpClient().Do(request)
+	if !debug {
+		return response, err
+	}
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelDebug, "request failed", append([]slog.Attr{
+			slog.String("url", request.URL.String()),
+			slog.Duration("elapsed", time.Since(start)),
+			slog.Any("error", err)}, attrs...)...)
+		return response, err
+	}
+	logger.LogAttrs(ctx, slog.LevelDebug, "received response", append([]slog.Attr{
+		slog.String("url", request.URL.String()),
+		slog.Int("status", response.StatusCode),
+		slog.Duration("elapsed", time.Since(start)),
+		slog.Int64("content_length", response.ContentLength),
+		headerAttrs(response.Header)}, attrs...)...)
+	return response, nil
+}
@@ -0,0 +1,47 @@
+package azuretexttospeech
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerRedactsCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("SYS49152-TOKEN"))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	az := &AzureCSTextToSpeech{SubscriptionKey: "SYS64738-KEY", tokenRefreshURL: ts.URL, textToSpeechURL: ts.URL}
+	WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))(az)
+
+	assert.NoError(t, az.refreshToken())
+	_, err := az.SynthesizeWithContext(context.Background(), VoiceParam{SpeechText: "hi", Voice: "en-US-AvaNeural", Locale: LocaleEnUS}, AudioOutput_riff_8khz_8bit_mono_alaw)
+	assert.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `"msg":"sending request"`)
+	assert.Contains(t, out, `"msg":"received response"`)
+	assert.Contains(t, out, `"attempt":1`)
+	assert.Contains(t, out, `"Ocp-Apim-Subscription-Key":"REDACTED"`)
+	assert.Contains(t, out, `"Authorization":"REDACTED"`)
+	assert.NotContains(t, out, "SYS64738-KEY")
+	assert.NotContains(t, out, "SYS49152-TOKEN")
+}
+
+func TestLoggerLevel(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	az := &AzureCSTextToSpeech{tokenRefreshURL: ts.URL}
+	WithLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))(az)
+	assert.NoError(t, az.refreshToken())
+	assert.Empty(t, buf.String(), "request metadata is only logged at debug level")
+}
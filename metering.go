@@ -0,0 +1,240 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx attributing synthesis requests made with it to tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set by WithTenant, or an empty string.
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// Usage describes a successful synthesis request.
+type Usage struct {
+	Tenant        string
+	Characters    int           // billable characters, see BillableCharacters
+	AudioDuration time.Duration // playing time of the audio, zero when it cannot be determined from the format
+	Format        AudioOutput
+	Time          time.Time
+}
+
+// UsageMeter receives the usage of every successful synthesis request, see WithUsageMeter. Requests abandoned
+// after they were sent, such as the slower request of a hedge, may be billed too and are reported without
+// AudioDuration.
+// RecordUsage is called synchronously and must be safe for concurrent use.
+type UsageMeter interface {
+	RecordUsage(ctx context.Context, usage Usage)
+}
+
+// UsageMeterFunc adapts an ordinary function to a UsageMeter.
+type UsageMeterFunc func(ctx context.Context, usage Usage)
+
+// RecordUsage calls f(ctx, usage).
+func (f UsageMeterFunc) RecordUsage(ctx context.Context, usage Usage) {
+	f(ctx, usage)
+}
+
+// ErrQuotaExceeded is returned, wrapped, when a request would take a tenant over its character budget.
+var ErrQuotaExceeded = errors.New("character quota exceeded")
+
+// QuotaStore persists the characters consumed by each tenant during a period. Periods are named "2006-01-02"
+// for days and "2006-01" for months.
+type QuotaStore interface {
+	// Usage returns the characters tenant consumed during period.
+	Usage(ctx context.Context, tenant, period string) (int64, error)
+	// Add records n more characters for tenant during period.
+	Add(ctx context.Context, tenant, period string, n int64) error
+}
+
+// Quota is a character budget, zero limits are unlimited.
+type Quota struct {
+	Daily   int64
+	Monthly int64
+}
+
+// QuotaEnforcer rejects requests of tenants which exhausted their character budget, see WithQuota. Usage is
+// checked before and recorded after a request, so concurrent requests of a tenant may overshoot its budget
+// by the size of the requests in flight. Days and months are in UTC.
+type QuotaEnforcer struct {
+	Store   QuotaStore
+	Default Quota            // budget of tenants missing from Tenants
+	Tenants map[string]Quota // budgets of individual tenants
+
+	now func() time.Time
+}
+
+func (q *QuotaEnforcer) periods() (day, month string) {
+	now := time.Now
+	if q.now != nil {
+		now = q.now
+	}
+	t := now().UTC()
+	return t.Format("2006-01-02"), t.Format("2006-01")
+}
+
+func (q *QuotaEnforcer) quota(tenant string) Quota {
+	if quota, ok := q.Tenants[tenant]; ok {
+		return quota
+	}
+	return q.Default
+}
+
+// Check returns an error wrapping ErrQuotaExceeded when characters more characters would exceed the budget
+// of tenant.
+func (q *QuotaEnforcer) Check(ctx context.Context, tenant string, characters int) error {
+	quota := q.quota(tenant)
+	day, month := q.periods()
+	for _, limit := range []struct {
+		period string
+		max    int64
+	}{{day, quota.Daily}, {month, quota.Monthly}} {
+		if limit.max == 0 {
+			continue
+		}
+		used, err := q.Store.Usage(ctx, tenant, limit.period)
+		if err != nil {
+			return fmt.Errorf("failed to read quota usage, %v", err)
+		}
+		if used+int64(characters) > limit.max {
+			return fmt.Errorf("tenant %q used %d of %d characters in %s, %w", tenant, used, limit.max, limit.period, ErrQuotaExceeded)
+		}
+	}
+	return nil
+}
+
+// Record adds characters to the usage of tenant.
+func (q *QuotaEnforcer) Record(ctx context.Context, tenant string, characters int) error {
+	day, month := q.periods()
+	if err := q.Store.Add(ctx, tenant, day, int64(characters)); err != nil {
+		return err
+	}
+	return q.Store.Add(ctx, tenant, month, int64(characters))
+}
+
+// MemoryQuotaStore keeps usage in memory, it is lost when the process exits.
+type MemoryQuotaStore struct {
+	mu    sync.Mutex
+	usage map[string]int64
+}
+
+// NewMemoryQuotaStore returns an empty MemoryQuotaStore.
+func NewMemoryQuotaStore() *MemoryQuotaStore {
+	return &MemoryQuotaStore{usage: make(map[string]int64)}
+}
+
+// Usage implements QuotaStore.
+func (s *MemoryQuotaStore) Usage(_ context.Context, tenant, period string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usage[period+"/"+tenant], nil
+}
+
+// Add implements QuotaStore.
+func (s *MemoryQuotaStore) Add(_ context.Context, tenant, period string, n int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage[period+"/"+tenant] += n
+	return nil
+}
+
+// FileQuotaStore keeps usage in a JSON file, so that budgets survive restarts. The file is rewritten on
+// every change, so it suits moderate traffic, and periods older than the one written are dropped.
+//
+// Processes sharing the file serialize their changes with an advisory lock on a ".lock" file next to it.
+// The lock is only available on Linux, macOS and the BSDs: elsewhere, and on network file systems which do
+// not honour flock, a file must only be used by a single process.
+type FileQuotaStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileQuotaStore returns a FileQuotaStore backed by the file at path, which is created on first use.
+func NewFileQuotaStore(path string) *FileQuotaStore {
+	return &FileQuotaStore{path: path}
+}
+
+// load reads the usage file, keyed by period and then tenant.
+func (s *FileQuotaStore) load() (map[string]map[string]int64, error) {
+	usage := make(map[string]map[string]int64)
+	b, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return usage, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &usage); err != nil {
+		return nil, fmt.Errorf("unable to decode quota file %s, %v", s.path, err)
+	}
+	return usage, nil
+}
+
+// Usage implements QuotaStore.
+func (s *FileQuotaStore) Usage(_ context.Context, tenant, period string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	usage, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	return usage[period][tenant], nil
+}
+
+// Add implements QuotaStore.
+func (s *FileQuotaStore) Add(_ context.Context, tenant, period string, n int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return fmt.Errorf("unable to lock quota file %s, %v", s.path, err)
+	}
+	defer unlock()
+	usage, err := s.load()
+	if err != nil {
+		return err
+	}
+	for p := range usage {
+		// periods of the same kind, days or months, sort by date.
+		if len(p) == len(period) && p < period {
+			delete(usage, p)
+		}
+	}
+	if usage[period] == nil {
+		usage[period] = make(map[string]int64)
+	}
+	usage[period][tenant] += n
+
+	b, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so that a crash never leaves a truncated file behind.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
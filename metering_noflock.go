@@ -0,0 +1,8 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package azuretexttospeech
+
+// lockFile does nothing on systems without flock, FileQuotaStore is then limited to a single process.
+func lockFile(path string) (unlock func(), err error) {
+	return func() {}, nil
+}
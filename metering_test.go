@@ -0,0 +1,127 @@
+package azuretexttospeech
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WqyJh/azuretexttospeech/audio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaEnforcer(t *testing.T) {
+	for name, store := range map[string]QuotaStore{
+		"memory": NewMemoryQuotaStore(),
+		"file":   NewFileQuotaStore(filepath.Join(t.TempDir(), "quota.json")),
+	} {
+		now := time.Date(2024, 3, 30, 23, 0, 0, 0, time.UTC)
+		q := &QuotaEnforcer{
+			Store:   store,
+			Default: Quota{Daily: 100, Monthly: 150},
+			Tenants: map[string]Quota{"unlimited": {}},
+			now:     func() time.Time { return now },
+		}
+		ctx := context.Background()
+
+		assert.NoError(t, q.Check(ctx, "acme", 100), name)
+		assert.NoError(t, q.Record(ctx, "acme", 90), name)
+		err := q.Check(ctx, "acme", 11)
+		assert.True(t, errors.Is(err, ErrQuotaExceeded), name)
+		assert.NoError(t, q.Check(ctx, "globex", 11), "tenants have separate budgets")
+		assert.NoError(t, q.Check(ctx, "unlimited", 1000000), name)
+
+		// the next day resets the daily, but not the monthly budget.
+		now = now.Add(2 * time.Hour)
+		assert.NoError(t, q.Check(ctx, "acme", 60), name)
+		assert.NoError(t, q.Record(ctx, "acme", 60), name)
+		assert.NoError(t, q.Check(ctx, "acme", 0), name)
+		assert.NoError(t, q.Record(ctx, "acme", 0), name)
+
+		assert.True(t, errors.Is(q.Check(ctx, "acme", 1), ErrQuotaExceeded), "%s: monthly budget is used up", name)
+
+		// the next month resets both budgets.
+		now = now.AddDate(0, 0, 2)
+		assert.NoError(t, q.Check(ctx, "acme", 100), name)
+	}
+}
+
+func TestFileQuotaStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quota.json")
+	ctx := context.Background()
+	assert.NoError(t, NewFileQuotaStore(path).Add(ctx, "acme", "2024-03", 42))
+	used, err := NewFileQuotaStore(path).Usage(ctx, "acme", "2024-03")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), used)
+
+	// writing a period drops the older periods of the same kind.
+	store := NewFileQuotaStore(path)
+	assert.NoError(t, store.Add(ctx, "acme", "2024-03-31", 1))
+	assert.NoError(t, store.Add(ctx, "acme", "2024-04", 7))
+	assert.NoError(t, store.Add(ctx, "acme", "2024-04-01", 7))
+	for period, expect := range map[string]int64{"2024-03": 0, "2024-03-31": 0, "2024-04": 7, "2024-04-01": 7} {
+		used, err := store.Usage(ctx, "acme", period)
+		assert.NoError(t, err)
+		assert.Equal(t, expect, used, period)
+	}
+}
+
+func TestFileQuotaStoreProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quota.json")
+	ctx := context.Background()
+	// stores of separate processes only share the file.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store := NewFileQuotaStore(path)
+			for j := 0; j < 25; j++ {
+				assert.NoError(t, store.Add(ctx, "acme", "2024-03", 1))
+			}
+		}()
+	}
+	wg.Wait()
+	used, err := NewFileQuotaStore(path).Usage(ctx, "acme", "2024-03")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), used)
+}
+
+func TestSynthesizeMetering(t *testing.T) {
+	format, _ := audio.ParseFormat(string(AudioOutput_riff_8khz_16bit_mono_pcm))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(audio.EncodeWAV(format, bytes.Repeat([]byte{0}, 8000)))
+	}))
+	defer ts.Close()
+
+	var mu sync.Mutex
+	var usage []Usage
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL}
+	WithUsageMeter(UsageMeterFunc(func(ctx context.Context, u Usage) {
+		mu.Lock()
+		defer mu.Unlock()
+		usage = append(usage, u)
+	}))(az)
+	WithQuota(&QuotaEnforcer{Store: NewMemoryQuotaStore(), Default: Quota{Daily: 10}})(az)
+
+	ctx := WithTenant(context.Background(), "acme")
+	param := VoiceParam{SpeechText: "Hello", Voice: "en-US-AvaNeural", Locale: LocaleEnUS}
+	_, err := az.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.NoError(t, err)
+	_, err = az.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.NoError(t, err)
+	_, err = az.SynthesizeWithContext(ctx, param, AudioOutput_riff_8khz_16bit_mono_pcm)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded), "third request exceeds the daily budget")
+
+	if assert.Len(t, usage, 2) {
+		assert.Equal(t, "acme", usage[0].Tenant)
+		assert.Equal(t, 5, usage[0].Characters)
+		assert.Equal(t, 500*time.Millisecond, usage[0].AudioDuration)
+		assert.Equal(t, AudioOutput_riff_8khz_16bit_mono_pcm, usage[0].Format)
+	}
+}
@@ -0,0 +1,295 @@
+package azuretexttospeech
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// NormalizeStage transforms speech text before it is rendered into SSML. Stages only receive plain
+// text, SSML markup contained in the speech text is passed through untouched.
+type NormalizeStage interface {
+	Normalize(text string, locale Locale) string
+}
+
+// NormalizeFunc adapts an ordinary function to a NormalizeStage.
+type NormalizeFunc func(text string, locale Locale) string
+
+// Normalize calls f(text, locale).
+func (f NormalizeFunc) Normalize(text string, locale Locale) string {
+	return f(text, locale)
+}
+
+// Normalizer is a pipeline of stages applied in order to `VoiceParam.SpeechText`, see WithNormalizer.
+type Normalizer []NormalizeStage
+
+// DefaultNormalizer returns a pipeline suited to text produced for screens, such as chat messages.
+func DefaultNormalizer() Normalizer {
+	return Normalizer{
+		StripMarkdown,
+		StripHTML,
+		ShortenURLs,
+		ExpandEmoji(DefaultEmojiNames),
+		ExpandCurrency,
+		SayAsDates,
+		SayAsTelephone,
+		SayAsNumbers,
+		CollapseWhitespace,
+	}
+}
+
+// Normalize runs text through every stage of the pipeline.
+func (n Normalizer) Normalize(text string, locale Locale) string {
+	for _, stage := range n {
+		text = stage.Normalize(text, locale)
+	}
+	return text
+}
+
+// textStage wraps fn so that it is only applied to the text between SSML tags. Text inside elements which
+// already control pronunciation (`<sub>`, `<phoneme>`, `<say-as>`) is left unchanged.
+func textStage(fn func(text string, locale Locale) string) NormalizeFunc {
+	return func(text string, locale Locale) string {
+		var result strings.Builder
+		for _, seg := range splitSSML(text) {
+			if seg.markup || seg.protected {
+				result.WriteString(seg.text)
+				continue
+			}
+			result.WriteString(fn(seg.text, locale))
+		}
+		return result.String()
+	}
+}
+
+// localeLanguage returns the lower case language subtag of locale, "en" for "en-US".
+func localeLanguage(locale Locale) string {
+	lang := string(locale)
+	if i := strings.IndexByte(lang, '-'); i != -1 {
+		lang = lang[:i]
+	}
+	return strings.ToLower(lang)
+}
+
+var markdownRules = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile("(?s)```[^\n]*\n(.*?)```"), "$1"},
+	{regexp.MustCompile("`([^`]*)`"), "$1"},
+	{regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`), "$1"},
+	{regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`), "$1"},
+	{regexp.MustCompile(`(?m)^[ \t]*([-*_][ \t]*){3,}$`), ""},
+	{regexp.MustCompile(`(?m)^[ \t]*#{1,6}[ \t]+`), ""},
+	{regexp.MustCompile(`(?m)^[ \t]*>[ \t]?`), ""},
+	{regexp.MustCompile(`(?m)^[ \t]*[-*+][ \t]+`), ""},
+	{regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`), "$2"},
+	{regexp.MustCompile(`~~(.+?)~~`), "$1"},
+	{regexp.MustCompile(`(^|[^\w*])\*([^*\n]+)\*([^\w*]|$)`), "$1$2$3"},
+	{regexp.MustCompile(`(^|[^\w_])_([^_\n]+)_([^\w_]|$)`), "$1$2$3"},
+}
+
+// StripMarkdown removes Markdown formatting, keeping the text of links, images and code.
+var StripMarkdown = textStage(func(text string, _ Locale) string {
+	for _, rule := range markdownRules {
+		text = rule.re.ReplaceAllString(text, rule.repl)
+	}
+	return text
+})
+
+// ssmlElements are the element names which StripHTML keeps.
+var ssmlElements = map[string]bool{
+	"speak": true, "voice": true, "lexicon": true, "prosody": true, "break": true, "p": true, "s": true,
+	"sub": true, "phoneme": true, "say-as": true, "emphasis": true, "audio": true, "bookmark": true,
+	"lang": true, "math": true, "mstts:express-as": true, "mstts:silence": true, "mstts:viseme": true,
+	"mstts:audioduration": true, "mstts:backgroundaudio": true,
+}
+
+// xmlTextEscaper escapes text for use as the content of an XML element. Unlike xmlAttr it keeps line breaks
+// and quotes, so that later normalization stages still see them.
+var xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// StripHTML removes tags which are not SSML elements and decodes HTML entities, escaping the
+// resulting text so that it remains valid inside an SSML document.
+var StripHTML = NormalizeFunc(func(text string, _ Locale) string {
+	var result strings.Builder
+	for _, seg := range splitSSML(text) {
+		if !seg.markup {
+			result.WriteString(xmlTextEscaper.Replace(html.UnescapeString(seg.text)))
+			continue
+		}
+		if name, _, _ := ssmlTagName(seg.text); ssmlElements[name] {
+			result.WriteString(seg.text)
+		} else {
+			// replace the tag with a space so words on either side of a <br> are not joined.
+			result.WriteString(" ")
+		}
+	}
+	return result.String()
+})
+
+var urlPattern = regexp.MustCompile(`\bhttps?://[^\s<>"']+`)
+
+// ShortenURLs replaces URLs with their host name, which is all a listener can usefully take in.
+var ShortenURLs = textStage(func(text string, _ Locale) string {
+	return urlPattern.ReplaceAllStringFunc(text, func(s string) string {
+		trimmed := strings.TrimRight(s, ".,;:!?)")
+		u, err := url.Parse(trimmed)
+		if err != nil || u.Host == "" {
+			return s
+		}
+		return strings.TrimPrefix(u.Hostname(), "www.") + s[len(trimmed):]
+	})
+})
+
+var whitespacePattern = regexp.MustCompile(`\s+`)
+
+// CollapseWhitespace replaces runs of whitespace with a single space and trims the text.
+var CollapseWhitespace = NormalizeFunc(func(text string, _ Locale) string {
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+})
+
+// EmojiNames maps emoji to the words spoken in their place, keyed by language subtag ("en").
+type EmojiNames map[string]map[string]string
+
+// DefaultEmojiNames names a set of commonly used emoji in English.
+var DefaultEmojiNames = EmojiNames{
+	"en": {
+		"😀": "grinning face", "😂": "face with tears of joy", "🙂": "slightly smiling face", "😉": "winking face",
+		"😢": "crying face", "❤": "red heart", "👍": "thumbs up", "👎": "thumbs down", "👋": "waving hand",
+		"🙏": "folded hands", "🎉": "party popper", "🔥": "fire", "✅": "check mark", "❌": "cross mark",
+		"⭐": "star", "🚀": "rocket", "💡": "light bulb", "⚠": "warning",
+	},
+}
+
+// isEmoji reports whether r is an emoji or a modifier which is part of an emoji sequence.
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF, // pictographs, emoticons, flags, skin tones
+		r >= 0x2600 && r <= 0x27BF, // miscellaneous symbols and dingbats
+		r >= 0x2B00 && r <= 0x2BFF, // arrows and stars
+		r == 0x200D, r == 0xFE0F, r == 0x20E3:
+		return true
+	}
+	return false
+}
+
+// isEmojiModifier reports whether r only modifies the emoji before it.
+func isEmojiModifier(r rune) bool {
+	return r == 0x200D || r == 0xFE0F || r == 0x20E3 || (r >= 0x1F3FB && r <= 0x1F3FF)
+}
+
+// DropEmoji removes emoji from the text.
+var DropEmoji = ExpandEmoji(nil)
+
+// ExpandEmoji replaces emoji with their names from names for the locale's language. Emoji without a name
+// are removed.
+func ExpandEmoji(names EmojiNames) NormalizeStage {
+	return textStage(func(text string, locale Locale) string {
+		table := names[localeLanguage(locale)]
+		runes := []rune(text)
+		var result strings.Builder
+		for i := 0; i < len(runes); {
+			if !isEmoji(runes[i]) {
+				result.WriteRune(runes[i])
+				i++
+				continue
+			}
+			// an emoji sequence is named after its first emoji, modifiers and emoji joined to it by a
+			// zero width joiner are part of the same sequence.
+			base := string(runes[i])
+			for i++; i < len(runes) && isEmojiModifier(runes[i]); i++ {
+				if runes[i] == 0x200D && i+1 < len(runes) {
+					i++
+				}
+			}
+			if name, ok := table[base]; ok {
+				result.WriteString(" " + name + " ")
+			}
+		}
+		return result.String()
+	})
+}
+
+// currencyNames are the spoken names of currency symbols, keyed by language subtag.
+var currencyNames = map[string]map[string]string{
+	"en": {"$": "dollars", "€": "euros", "£": "pounds", "¥": "yen"},
+	"de": {"$": "Dollar", "€": "Euro", "£": "Pfund", "¥": "Yen"},
+	"es": {"$": "dólares", "€": "euros", "£": "libras", "¥": "yenes"},
+	"fr": {"$": "dollars", "€": "euros", "£": "livres", "¥": "yens"},
+	"it": {"$": "dollari", "€": "euro", "£": "sterline", "¥": "yen"},
+	"pt": {"$": "dólares", "€": "euros", "£": "libras", "¥": "ienes"},
+}
+
+var currencyPattern = regexp.MustCompile(`([$€£¥])\s?(\d+(?:[.,]\d+)*)`)
+
+// ExpandCurrency moves currency symbols after the amount and spells them out, "$5" is read as
+// "5 dollars". Locales without known currency names are left unchanged.
+var ExpandCurrency = textStage(func(text string, locale Locale) string {
+	names, ok := currencyNames[localeLanguage(locale)]
+	if !ok {
+		return text
+	}
+	return currencyPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := currencyPattern.FindStringSubmatch(s)
+		return m[2] + " " + names[m[1]]
+	})
+})
+
+var (
+	isoDatePattern   = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b`)
+	slashDatePattern = regexp.MustCompile(`\b\d{1,2}/\d{1,2}/\d{4}\b`)
+)
+
+// monthFirstLocales write numeric dates with the month first.
+var monthFirstLocales = map[Locale]bool{LocaleEnUS: true, "en-PH": true, "es-US": true}
+
+// SayAsDates marks ISO 8601 dates and numeric dates written with slashes as dates. Whether a slash date
+// is read as month/day or day/month depends on the locale.
+var SayAsDates = textStage(func(text string, locale Locale) string {
+	text = isoDatePattern.ReplaceAllString(text, "<say-as interpret-as='date' format='ymd'>$0</say-as>")
+	format := "dmy"
+	if monthFirstLocales[locale] {
+		format = "mdy"
+	}
+	return replaceOutsideMarkup(text, slashDatePattern, fmt.Sprintf("<say-as interpret-as='date' format='%s'>$0</say-as>", format))
+})
+
+var telephonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s-]?)?(?:\(\d{2,4}\)\s?|\b\d{2,4}[\s-])\d{3,4}[\s-]\d{3,4}\b`)
+
+// SayAsTelephone marks telephone numbers so that they are read digit group by digit group.
+var SayAsTelephone = textStage(func(text string, _ Locale) string {
+	return telephonePattern.ReplaceAllStringFunc(text, func(s string) string {
+		return "<say-as interpret-as='telephone'>" + s + "</say-as>"
+	})
+})
+
+var (
+	pointDecimalNumberPattern = regexp.MustCompile(`\b\d{1,3}(?:,\d{3})+(?:\.\d+)?\b`)
+	commaDecimalNumberPattern = regexp.MustCompile(`\b\d{1,3}(?:\.\d{3})+(?:,\d+)?\b`)
+)
+
+// commaDecimalLanguages group thousands with a point and use a comma as the decimal separator.
+var commaDecimalLanguages = map[string]bool{
+	"da": true, "de": true, "es": true, "id": true, "it": true, "nl": true, "pt": true, "ro": true, "tr": true, "vi": true,
+}
+
+// SayAsNumbers marks numbers written with thousands separators, "1,250,000" in English or "1.250.000"
+// in German, as cardinal numbers.
+var SayAsNumbers = textStage(func(text string, locale Locale) string {
+	pattern := pointDecimalNumberPattern
+	if commaDecimalLanguages[localeLanguage(locale)] {
+		pattern = commaDecimalNumberPattern
+	}
+	return replaceOutsideMarkup(text, pattern, "<say-as interpret-as='cardinal'>$0</say-as>")
+})
+
+// replaceOutsideMarkup is regexp.ReplaceAllString restricted to the plain text of text, so that markup
+// added by an earlier replacement is not matched again.
+func replaceOutsideMarkup(text string, re *regexp.Regexp, repl string) string {
+	return textStage(func(s string, _ Locale) string {
+		return re.ReplaceAllString(s, repl)
+	})(text, "")
+}
@@ -0,0 +1,63 @@
+package azuretexttospeech
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeStages(t *testing.T) {
+	tests := []struct {
+		name   string
+		stage  NormalizeStage
+		text   string
+		locale Locale
+		expect string
+	}{
+		{"markdown", StripMarkdown, "# Title\n- **bold** and *italic* with `code`\n> see [the docs](https://example.com)", LocaleEnUS,
+			"Title\nbold and italic with code\nsee the docs"},
+		{"markdown keeps snake_case", StripMarkdown, "call my_func_name now", LocaleEnUS, "call my_func_name now"},
+		{"html", StripHTML, "<b>Fish &amp; chips</b><br>now <break time='1s'/>&lt;3", LocaleEnUS,
+			" Fish &amp; chips  now <break time='1s'/>&lt;3"},
+		{"html keeps line breaks and quotes", StripHTML, "Hello\nworld, don't &quot;stop&quot;", LocaleEnUS, "Hello\nworld, don't \"stop\""},
+		{"html keeps comparisons", StripHTML, "a < b and c > d, <i>x</i> <= 2", LocaleEnUS, "a &lt; b and c &gt; d,  x  &lt;= 2"},
+		{"urls", ShortenURLs, "Visit https://www.example.com/a/b?c=d, today.", LocaleEnUS, "Visit example.com, today."},
+		{"emoji expand", ExpandEmoji(DefaultEmojiNames), "Great👍🏽🎉!", LocaleEnUS, "Great thumbs up  party popper !"},
+		{"emoji drop", DropEmoji, "Great👍🏽 👨‍👩‍👧!", LocaleEnUS, "Great !"},
+		{"emoji unknown locale", ExpandEmoji(DefaultEmojiNames), "Super👍", LocaleDeDE, "Super"},
+		{"currency en", ExpandCurrency, "It costs $12.50 or €10.", LocaleEnUS, "It costs 12.50 dollars or 10 euros."},
+		{"currency amount ends at a space", ExpandCurrency, "Pay $5 2 times", LocaleEnUS, "Pay 5 dollars 2 times"},
+		{"currency de", ExpandCurrency, "Nur €10", LocaleDeDE, "Nur 10 Euro"},
+		{"currency unknown locale", ExpandCurrency, "$10", LocaleJaJP, "$10"},
+		{"dates en-US", SayAsDates, "On 03/05/2024 or 2024-03-05", LocaleEnUS,
+			"On <say-as interpret-as='date' format='mdy'>03/05/2024</say-as> or <say-as interpret-as='date' format='ymd'>2024-03-05</say-as>"},
+		{"dates en-GB", SayAsDates, "On 03/05/2024", LocaleEnGB, "On <say-as interpret-as='date' format='dmy'>03/05/2024</say-as>"},
+		{"telephone", SayAsTelephone, "Call +1 (555) 123-4567 or 555-123-4567.", LocaleEnUS,
+			"Call <say-as interpret-as='telephone'>+1 (555) 123-4567</say-as> or <say-as interpret-as='telephone'>555-123-4567</say-as>."},
+		{"numbers en", SayAsNumbers, "1,250,000.5 people, 2024", LocaleEnUS, "<say-as interpret-as='cardinal'>1,250,000.5</say-as> people, 2024"},
+		{"numbers de", SayAsNumbers, "1.250.000 Menschen", LocaleDeDE, "<say-as interpret-as='cardinal'>1.250.000</say-as> Menschen"},
+		{"whitespace", CollapseWhitespace, "  a \n\t b  ", LocaleEnUS, "a b"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expect, tt.stage.Normalize(tt.text, tt.locale), tt.name)
+	}
+}
+
+func TestDefaultNormalizer(t *testing.T) {
+	text := "**Order** 1,200 units for $5 each 🚀 — details at https://contoso.com/orders <say-as interpret-as='characters'>1,200</say-as>"
+	expect := "Order <say-as interpret-as='cardinal'>1,200</say-as> units for 5 dollars each rocket — details at contoso.com " +
+		"<say-as interpret-as='characters'>1,200</say-as>"
+	assert.Equal(t, expect, DefaultNormalizer().Normalize(text, LocaleEnUS))
+
+	assert.Equal(t, "Hello world, don't stop.", DefaultNormalizer().Normalize("Hello\nworld, don't stop.", LocaleEnUS))
+}
+
+func TestNormalizeFuncStage(t *testing.T) {
+	upper := NormalizeFunc(func(text string, _ Locale) string { return text + "!" })
+	n := Normalizer{upper, upper}
+	assert.Equal(t, "hi!!", n.Normalize("hi", LocaleEnUS))
+
+	az := &AzureCSTextToSpeech{}
+	WithNormalizer(n)(az)
+	assert.Equal(t, "hi!!", az.prepareParam(VoiceParam{SpeechText: "hi"}).SpeechText)
+}
@@ -0,0 +1,145 @@
+package azuretexttospeech
+
+import (
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configures optional behaviour of the AzureCSTextToSpeech client, see New.
+type Option func(*AzureCSTextToSpeech)
+
+// WithPronunciationDictionary rewrites `VoiceParam.SpeechText` using d before every synthesis request.
+func WithPronunciationDictionary(d *PronunciationDictionary) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.pronunciations = d
+	}
+}
+
+// WithNormalizer runs `VoiceParam.SpeechText` through n before every synthesis request. Normalization
+// happens before the pronunciation dictionary is applied.
+func WithNormalizer(n Normalizer) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.normalizer = n
+	}
+}
+
+// WithUsageMeter reports the usage of every synthesis request which may be billed to m, attributed to the tenant
+// set on the request context with WithTenant, see UsageMeter.
+func WithUsageMeter(m UsageMeter) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.usageMeter = m
+	}
+}
+
+// WithQuota rejects synthesis requests with an error wrapping ErrQuotaExceeded once the tenant set on the
+// request context with WithTenant has used up its character budget.
+func WithQuota(q *QuotaEnforcer) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.quota = q
+	}
+}
+
+// WithTracerProvider records a span for every request to the Speech service using tp. Tracing is disabled by
+// default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(az *AzureCSTextToSpeech) {
+		var mp metric.MeterProvider
+		if az.tel != nil {
+			mp = az.tel.meterProvider
+		}
+		az.tel = newTelemetry(tp, mp)
+	}
+}
+
+// WithMeterProvider records request duration, time to first byte, error and token refresh failure metrics
+// using mp. Metrics are disabled by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(az *AzureCSTextToSpeech) {
+		var tp trace.TracerProvider
+		if az.tel != nil {
+			tp = az.tel.tracerProvider
+		}
+		az.tel = newTelemetry(tp, mp)
+	}
+}
+
+// WithLogger sends the log output of the client to logger instead of slog.Default(). Failures, such as a
+// failed token refresh, are logged at error level and request and response metadata at debug level, with the
+// subscription key and access token redacted.
+func WithLogger(logger *slog.Logger) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.log = logger
+	}
+}
+
+// WithCircuitBreaker fails synthesis requests fast with ErrCircuitOpen while b is open. A CircuitBreaker tracks
+// a single endpoint, so every client, including each region of a FailoverClient, needs its own.
+func WithCircuitBreaker(b *CircuitBreaker) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.breaker = b
+	}
+}
+
+// WithHTTPClient sends all requests with c instead of a client shared by all clients of the package.
+func WithHTTPClient(c *http.Client) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.client = c
+	}
+}
+
+// WithTransport sends all requests over a connection pool of its own configured by cfg, see NewTransport.
+func WithTransport(cfg TransportConfig) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.client = &http.Client{Transport: NewTransport(cfg)}
+	}
+}
+
+// WithHedging sends a duplicate of slow synthesis requests to the same region, as configured by h. Use
+// WithRegionHedging to send the duplicate to another region of a FailoverClient instead.
+func WithHedging(h *Hedger) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.hedger = h
+	}
+}
+
+// WithStreamLookahead synthesizes up to n sentences of a stream ahead of the one being played, see
+// SynthesizeStream. The default is 3.
+func WithStreamLookahead(n int) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.streamLookahead = n
+	}
+}
+
+// WithMaxSSMLCharacters rejects caller supplied SSML documents of more than n billable characters before
+// sending them, see MaxBillableCharacters.
+func WithMaxSSMLCharacters(n int) Option {
+	return func(az *AzureCSTextToSpeech) {
+		az.maxSSMLCharacters = n
+	}
+}
+
+// Endpoints overrides the URLs of the Speech service, for example to use a private endpoint or a Speech
+// container. Empty fields keep the URL of the region.
+type Endpoints struct {
+	TextToSpeech string // e.g. "https://eastus.tts.speech.microsoft.com/cognitiveservices/v1"
+	TokenRefresh string // e.g. "https://eastus.api.cognitive.microsoft.com/sts/v1.0/issueToken"
+	VoiceList    string // e.g. "https://eastus.tts.speech.microsoft.com/cognitiveservices/voices/list"
+}
+
+// WithEndpoints sends requests to the URLs of e instead of the public endpoints of the region.
+func WithEndpoints(e Endpoints) Option {
+	return func(az *AzureCSTextToSpeech) {
+		if e.TextToSpeech != "" {
+			az.textToSpeechURL = e.TextToSpeech
+		}
+		if e.TokenRefresh != "" {
+			az.tokenRefreshURL = e.TokenRefresh
+		}
+		if e.VoiceList != "" {
+			az.voiceServiceListURL = e.VoiceList
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package azuretexttospeech
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pronunciation maps a word (or regular expression) to the way it should be spoken. Matches are replaced
+// with a `<sub alias>` element when Alias is set, or a `<phoneme ph>` element when Phoneme is set.
+// See: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/speech-synthesis-markup-pronunciation
+type Pronunciation struct {
+	Match         string           // the word to replace, or a regular expression when Regexp is set
+	Regexp        bool             // treat Match as a regular expression
+	CaseSensitive bool             // match case exactly, matches ignore case by default
+	Locale        Locale           // restricts the entry to a single locale, empty applies it to every locale
+	Alias         string           // text spoken in place of the match
+	Phoneme       string           // pronunciation of the match, spelled in Alphabet
+	Alphabet      PhoneticAlphabet // alphabet of Phoneme, defaults to AlphabetIPA
+}
+
+type compiledPronunciation struct {
+	Pronunciation
+	re *regexp.Regexp
+}
+
+// PronunciationDictionary rewrites speech text using a list of Pronunciation entries before it is sent
+// for synthesis. Matches only replace whole words and never touch SSML markup or text already wrapped
+// in `<sub>` or `<phoneme>` elements.
+type PronunciationDictionary struct {
+	entries []compiledPronunciation
+}
+
+// NewPronunciationDictionary validates and compiles entries. When several entries match at the same
+// position of the text, the one listed first wins.
+func NewPronunciationDictionary(entries ...Pronunciation) (*PronunciationDictionary, error) {
+	d := &PronunciationDictionary{}
+	for i, p := range entries {
+		if p.Match == "" {
+			return nil, fmt.Errorf("pronunciation %d has an empty match", i)
+		}
+		if (p.Alias == "") == (p.Phoneme == "") {
+			return nil, fmt.Errorf("pronunciation %d (%s) requires exactly one of alias or phoneme", i, p.Match)
+		}
+		if p.Phoneme != "" && p.Alphabet == "" {
+			p.Alphabet = AlphabetIPA
+		}
+		if p.Phoneme != "" && !p.Alphabet.IsValid() {
+			return nil, fmt.Errorf("pronunciation %d (%s) has unsupported phonetic alphabet %q", i, p.Match, p.Alphabet)
+		}
+
+		expr := p.Match
+		if !p.Regexp {
+			expr = regexp.QuoteMeta(expr)
+		}
+		if !p.CaseSensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("pronunciation %d has an invalid expression, %v", i, err)
+		}
+		d.entries = append(d.entries, compiledPronunciation{Pronunciation: p, re: re})
+	}
+	return d, nil
+}
+
+// Apply returns text with every entry applicable to locale substituted.
+func (d *PronunciationDictionary) Apply(text string, locale Locale) string {
+	var entries []compiledPronunciation
+	for _, e := range d.entries {
+		if e.Locale == "" || strings.EqualFold(string(e.Locale), string(locale)) {
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) == 0 {
+		return text
+	}
+
+	var result strings.Builder
+	for _, seg := range splitSSML(text) {
+		if seg.markup || seg.protected {
+			result.WriteString(seg.text)
+			continue
+		}
+		result.WriteString(applyPronunciations(seg.text, entries))
+	}
+	return result.String()
+}
+
+type pronunciationMatch struct {
+	start, end int
+	entry      *compiledPronunciation
+}
+
+// applyPronunciations replaces the matches in a plain text segment. All entries are matched against
+// the original text so a replacement is never rewritten by a later entry.
+func applyPronunciations(text string, entries []compiledPronunciation) string {
+	var matches []pronunciationMatch
+	for i := range entries {
+		for _, loc := range entries[i].re.FindAllStringIndex(text, -1) {
+			if loc[0] == loc[1] || !isWordBoundary(text, loc[0], loc[1]) {
+				continue
+			}
+			matches = append(matches, pronunciationMatch{start: loc[0], end: loc[1], entry: &entries[i]})
+		}
+	}
+	if len(matches) == 0 {
+		return text
+	}
+
+	var result strings.Builder
+	pos := 0
+	for pos < len(text) {
+		next := -1
+		for i, m := range matches {
+			if m.start >= pos && (next == -1 || m.start < matches[next].start) {
+				next = i
+			}
+		}
+		if next == -1 {
+			break
+		}
+		m := matches[next]
+		result.WriteString(text[pos:m.start])
+		word := text[m.start:m.end]
+		if m.entry.Alias != "" {
+			fmt.Fprintf(&result, "<sub alias='%s'>%s</sub>", xmlAttr(m.entry.Alias), word)
+		} else {
+			fmt.Fprintf(&result, "<phoneme alphabet='%s' ph='%s'>%s</phoneme>", m.entry.Alphabet, xmlAttr(m.entry.Phoneme), word)
+		}
+		pos = m.end
+	}
+	result.WriteString(text[pos:])
+	return result.String()
+}
+
+// isWordBoundary reports whether text[start:end] is not part of a longer word.
+func isWordBoundary(text string, start, end int) bool {
+	first, _ := utf8.DecodeRuneInString(text[start:])
+	last, _ := utf8.DecodeLastRuneInString(text[:end])
+	if isWordRune(first) && start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(text[:start]); isWordRune(r) {
+			return false
+		}
+	}
+	if isWordRune(last) && end < len(text) {
+		if r, _ := utf8.DecodeRuneInString(text[end:]); isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// ssmlSegment is a piece of speech text. Markup segments hold a single tag, protected segments hold
+// text inside elements whose content must not be rewritten.
+type ssmlSegment struct {
+	text      string
+	markup    bool
+	protected bool
+}
+
+// protectedElements are the SSML elements whose text content already controls pronunciation.
+var protectedElements = map[string]bool{"sub": true, "phoneme": true, "say-as": true}
+
+// splitSSML splits speech text which may contain SSML markup into tags and text. A '<' which does not start a
+// tag, as in "a < b", is text.
+func splitSSML(text string) []ssmlSegment {
+	var segments []ssmlSegment
+	depth := 0
+	for len(text) > 0 {
+		open := tagStart(text)
+		if open != 0 {
+			if open == -1 {
+				open = len(text)
+			}
+			segments = append(segments, ssmlSegment{text: text[:open], protected: depth > 0})
+			text = text[open:]
+			continue
+		}
+		end := strings.IndexByte(text, '>')
+		if end == -1 {
+			// not a complete tag, leave the remainder untouched.
+			segments = append(segments, ssmlSegment{text: text, markup: true})
+			break
+		}
+		tag := text[:end+1]
+		name, closing, selfClosing := ssmlTagName(tag)
+		if protectedElements[name] && !selfClosing {
+			if closing && depth > 0 {
+				depth--
+			} else if !closing {
+				depth++
+			}
+		}
+		segments = append(segments, ssmlSegment{text: tag, markup: true})
+		text = text[end+1:]
+	}
+	return segments
+}
+
+// tagStart returns the index of the first '<' of text which is followed by an element name, '/' or '!', or -1.
+func tagStart(text string) int {
+	for i := 0; i < len(text)-1; i++ {
+		if text[i] != '<' {
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(text[i+1:])
+		if r == '/' || r == '!' || r == '_' || unicode.IsLetter(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ssmlTagName returns the element name of a tag such as `<sub alias='x'>` or `</sub>`.
+func ssmlTagName(tag string) (name string, closing, selfClosing bool) {
+	inner := strings.TrimSuffix(strings.TrimPrefix(tag, "<"), ">")
+	if strings.HasPrefix(inner, "/") {
+		closing = true
+		inner = inner[1:]
+	}
+	if strings.HasSuffix(inner, "/") {
+		selfClosing = true
+		inner = inner[:len(inner)-1]
+	}
+	if i := strings.IndexFunc(inner, unicode.IsSpace); i != -1 {
+		inner = inner[:i]
+	}
+	return strings.ToLower(inner), closing, selfClosing
+}
@@ -0,0 +1,71 @@
+package azuretexttospeech
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPronunciationDictionaryApply(t *testing.T) {
+	d, err := NewPronunciationDictionary(
+		Pronunciation{Match: "Contoso", Phoneme: "ˈkɒn.təʊ.səʊ"},
+		Pronunciation{Match: "SQL", Alias: "sequel", CaseSensitive: true},
+		Pronunciation{Match: `v(\d+)\.(\d+)`, Regexp: true, Alias: "version"},
+		Pronunciation{Match: "Müller", Alias: "Mueller", Locale: LocaleDeDE},
+		Pronunciation{Match: "R&D", Alias: "research & development"},
+	)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		text   string
+		locale Locale
+		expect string
+	}{
+		{"contoso rocks", LocaleEnUS, "<phoneme alphabet='ipa' ph='ˈkɒn.təʊ.səʊ'>contoso</phoneme> rocks"},
+		{"Contosos are not matched", LocaleEnUS, "Contosos are not matched"},
+		{"SQL and sql", LocaleEnUS, "<sub alias='sequel'>SQL</sub> and sql"},
+		{"update to v1.2 today", LocaleEnUS, "update to <sub alias='version'>v1.2</sub> today"},
+		{"Müller", LocaleEnUS, "Müller"},
+		{"Müller Müllers", LocaleDeDE, "<sub alias='Mueller'>Müller</sub> Müllers"},
+		{"R&D team", LocaleEnUS, "<sub alias='research &amp; development'>R&D</sub> team"},
+		// markup and content of pronunciation elements are left alone.
+		{"<break time='SQL'/>SQL <sub alias='x'>SQL</sub> SQL", LocaleEnUS,
+			"<break time='SQL'/><sub alias='sequel'>SQL</sub> <sub alias='x'>SQL</sub> <sub alias='sequel'>SQL</sub>"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expect, d.Apply(tt.text, tt.locale), tt.text)
+	}
+}
+
+func TestNewPronunciationDictionaryErrors(t *testing.T) {
+	for _, p := range []Pronunciation{
+		{Alias: "x"},
+		{Match: "a"},
+		{Match: "a", Alias: "b", Phoneme: "c"},
+		{Match: "a", Phoneme: "c", Alphabet: "klingon"},
+		{Match: "(", Regexp: true, Alias: "b"},
+	} {
+		_, err := NewPronunciationDictionary(p)
+		assert.Error(t, err, "%+v", p)
+	}
+}
+
+func TestSynthesizeAppliesPronunciations(t *testing.T) {
+	d, err := NewPronunciationDictionary(Pronunciation{Match: "BTW", Alias: "by the way"})
+	assert.NoError(t, err)
+
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer ts.Close()
+
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL}
+	WithPronunciationDictionary(d)(az)
+	_, err = az.Synthesize(VoiceParam{SpeechText: "BTW", Voice: "en-US-GuyNeural", Locale: LocaleEnUS}, AudioOutput_riff_8khz_8bit_mono_alaw)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "<sub alias='by the way'>BTW</sub>")
+}
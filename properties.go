@@ -49,16 +49,56 @@ const (
 	AudioOutput_riff_48khz_16bit_mono_pcm   AudioOutput = "riff-48khz-16bit-mono-pcm"
 )
 
+// AudioOutputs lists the supported audio output formats.
+var AudioOutputs = []AudioOutput{
+	AudioOutput_amr_wb_16000hz,
+	AudioOutput_audio_16khz_16bit_32kbps_mono_opus,
+	AudioOutput_audio_16khz_32kbitrate_mono_mp3,
+	AudioOutput_audio_16khz_64kbitrate_mono_mp3,
+	AudioOutput_audio_16khz_128kbitrate_mono_mp3,
+	AudioOutput_audio_24khz_16bit_24kbps_mono_opus,
+	AudioOutput_audio_24khz_16bit_48kbps_mono_opus,
+	AudioOutput_audio_24khz_48kbitrate_mono_mp3,
+	AudioOutput_audio_24khz_96kbitrate_mono_mp3,
+	AudioOutput_audio_24khz_160kbitrate_mono_mp3,
+	AudioOutput_audio_48khz_96kbitrate_mono_mp3,
+	AudioOutput_audio_48khz_192kbitrate_mono_mp3,
+	AudioOutput_ogg_16khz_16bit_mono_opus,
+	AudioOutput_ogg_24khz_16bit_mono_opus,
+	AudioOutput_ogg_48khz_16bit_mono_opus,
+	AudioOutput_raw_8khz_8bit_mono_alaw,
+	AudioOutput_raw_8khz_8bit_mono_mulaw,
+	AudioOutput_raw_8khz_16bit_mono_pcm,
+	AudioOutput_raw_16khz_16bit_mono_pcm,
+	AudioOutput_raw_16khz_16bit_mono_truesilk,
+	AudioOutput_raw_22050hz_16bit_mono_pcm,
+	AudioOutput_raw_24khz_16bit_mono_pcm,
+	AudioOutput_raw_24khz_16bit_mono_truesilk,
+	AudioOutput_raw_44100hz_16bit_mono_pcm,
+	AudioOutput_raw_48khz_16bit_mono_pcm,
+	AudioOutput_webm_16khz_16bit_mono_opus,
+	AudioOutput_webm_24khz_16bit_24kbps_mono_opus,
+	AudioOutput_webm_24khz_16bit_mono_opus,
+	AudioOutput_riff_8khz_8bit_mono_alaw,
+	AudioOutput_riff_8khz_8bit_mono_mulaw,
+	AudioOutput_riff_8khz_16bit_mono_pcm,
+	AudioOutput_riff_22050hz_16bit_mono_pcm,
+	AudioOutput_riff_24khz_16bit_mono_pcm,
+	AudioOutput_riff_44100hz_16bit_mono_pcm,
+	AudioOutput_riff_48khz_16bit_mono_pcm,
+}
+
 // Gender type for the digitized language
 //
 //go:generate enumer -type=Gender -linecomment -json
 type Gender int
 
 const (
-	// GenderMale , GenderFemale are the static Gender constants for digitized voices.
+	// GenderMale , GenderFemale, GenderNeutral are the static Gender constants for digitized voices.
 	// See Gender in https://docs.microsoft.com/en-us/azure/cognitive-services/speech-service/language-support#standard-voices for breakdown
-	GenderMale   Gender = iota // Male
-	GenderFemale               // Female
+	GenderMale    Gender = iota // Male
+	GenderFemale                // Female
+	GenderNeutral               // Neutral
 )
 
 // Locale references the language or locale for text-to-speech.
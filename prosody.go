@@ -0,0 +1,232 @@
+package azuretexttospeech
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Prosody adjusts the rate, pitch and volume of the synthesized speech, rendered as a `<prosody>` element.
+// Values are built with the constants and helpers of ProsodyRate, ProsodyPitch and ProsodyVolume; empty
+// values keep the voice's default.
+// See: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/speech-synthesis-markup-voice#adjust-prosody
+type Prosody struct {
+	Rate    ProsodyRate
+	Pitch   ProsodyPitch
+	Volume  ProsodyVolume
+	Contour []ContourPoint
+}
+
+// ProsodyRate is the speaking rate of the voice.
+type ProsodyRate string
+
+const (
+	RateXSlow   ProsodyRate = "x-slow"
+	RateSlow    ProsodyRate = "slow"
+	RateMedium  ProsodyRate = "medium"
+	RateFast    ProsodyRate = "fast"
+	RateXFast   ProsodyRate = "x-fast"
+	RateDefault ProsodyRate = "default"
+)
+
+// RateMultiplier returns a rate relative to the default, 0.5 speaks at half and 2 at twice the default speed.
+func RateMultiplier(m float64) ProsodyRate {
+	return ProsodyRate(formatFloat(m))
+}
+
+// RatePercent returns a rate changed by p percent, -20 speaks 20% slower than the default.
+func RatePercent(p float64) ProsodyRate {
+	return ProsodyRate(formatSigned(p) + "%")
+}
+
+// ProsodyPitch is the baseline pitch of the voice.
+type ProsodyPitch string
+
+const (
+	PitchXLow    ProsodyPitch = "x-low"
+	PitchLow     ProsodyPitch = "low"
+	PitchMedium  ProsodyPitch = "medium"
+	PitchHigh    ProsodyPitch = "high"
+	PitchXHigh   ProsodyPitch = "x-high"
+	PitchDefault ProsodyPitch = "default"
+)
+
+// PitchHz returns an absolute pitch in hertz.
+func PitchHz(hz float64) ProsodyPitch {
+	return ProsodyPitch(formatFloat(hz) + "Hz")
+}
+
+// PitchRelativeHz returns a pitch changed by hz hertz.
+func PitchRelativeHz(hz float64) ProsodyPitch {
+	return ProsodyPitch(formatSigned(hz) + "Hz")
+}
+
+// PitchSemitones returns a pitch changed by st semitones.
+func PitchSemitones(st float64) ProsodyPitch {
+	return ProsodyPitch(formatSigned(st) + "st")
+}
+
+// PitchPercent returns a pitch changed by p percent.
+func PitchPercent(p float64) ProsodyPitch {
+	return ProsodyPitch(formatSigned(p) + "%")
+}
+
+// ProsodyVolume is the volume of the voice.
+type ProsodyVolume string
+
+const (
+	VolumeSilent  ProsodyVolume = "silent"
+	VolumeXSoft   ProsodyVolume = "x-soft"
+	VolumeSoft    ProsodyVolume = "soft"
+	VolumeMedium  ProsodyVolume = "medium"
+	VolumeLoud    ProsodyVolume = "loud"
+	VolumeXLoud   ProsodyVolume = "x-loud"
+	VolumeDefault ProsodyVolume = "default"
+)
+
+// VolumeLevel returns an absolute volume from 0 (silent) to 100 (loudest).
+func VolumeLevel(v float64) ProsodyVolume {
+	return ProsodyVolume(formatFloat(v))
+}
+
+// VolumeRelative returns a volume changed by d on the 0 to 100 scale.
+func VolumeRelative(d float64) ProsodyVolume {
+	return ProsodyVolume(formatSigned(d))
+}
+
+// VolumePercent returns a volume changed by p percent.
+func VolumePercent(p float64) ProsodyVolume {
+	return ProsodyVolume(formatSigned(p) + "%")
+}
+
+// ContourPoint sets the pitch at Position, a percentage of the duration of the text.
+type ContourPoint struct {
+	Position float64
+	Pitch    ProsodyPitch
+}
+
+func formatSigned(f float64) string {
+	if f < 0 {
+		return formatFloat(f)
+	}
+	return "+" + formatFloat(f)
+}
+
+// Validate checks that every value is well formed and within the range accepted by the Speech service.
+func (p *Prosody) Validate() error {
+	if err := p.Rate.validate(); err != nil {
+		return err
+	}
+	if err := p.Pitch.validate(); err != nil {
+		return err
+	}
+	if err := p.Volume.validate(); err != nil {
+		return err
+	}
+	for i, c := range p.Contour {
+		if c.Position < 0 || c.Position > 100 {
+			return fmt.Errorf("contour point %d position %v is out of range, expected 0 to 100", i, c.Position)
+		}
+		if i > 0 && c.Position < p.Contour[i-1].Position {
+			return fmt.Errorf("contour point %d is out of order", i)
+		}
+		if !c.Pitch.isRelative() {
+			return fmt.Errorf("contour point %d pitch %q must be a relative change", i, c.Pitch)
+		}
+		if err := c.Pitch.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contour renders the contour points as the value of the contour attribute, "(0%,+20Hz) (50%,-2st)".
+func (p *Prosody) contour() string {
+	points := make([]string, len(p.Contour))
+	for i, c := range p.Contour {
+		points[i] = fmt.Sprintf("(%s%%,%s)", formatFloat(c.Position), c.Pitch)
+	}
+	return strings.Join(points, " ")
+}
+
+// parseProsodyNumber splits values such as "+20Hz" into the number, whether it carries a sign and whether it
+// ends with unit.
+func parseProsodyNumber(value, unit string) (n float64, signed, hasUnit bool, err error) {
+	if unit != "" && strings.HasSuffix(value, unit) {
+		value = strings.TrimSuffix(value, unit)
+		hasUnit = true
+	}
+	signed = strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-")
+	n, err = strconv.ParseFloat(value, 64)
+	if err == nil && (math.IsNaN(n) || math.IsInf(n, 0)) {
+		// NaN passes every range check.
+		err = fmt.Errorf("%q is not a finite number", value)
+	}
+	return n, signed, hasUnit, err
+}
+
+func (r ProsodyRate) validate() error {
+	switch r {
+	case "", RateXSlow, RateSlow, RateMedium, RateFast, RateXFast, RateDefault:
+		return nil
+	}
+	n, _, percent, err := parseProsodyNumber(string(r), "%")
+	if err != nil {
+		return fmt.Errorf("invalid prosody rate %q", r)
+	}
+	if percent && (n < -50 || n > 100) {
+		return fmt.Errorf("prosody rate %q is out of range, expected -50%% to +100%%", r)
+	}
+	if !percent && (n < 0.5 || n > 2) {
+		return fmt.Errorf("prosody rate %q is out of range, expected 0.5 to 2", r)
+	}
+	return nil
+}
+
+func (p ProsodyPitch) isRelative() bool {
+	return strings.HasPrefix(string(p), "+") || strings.HasPrefix(string(p), "-")
+}
+
+func (p ProsodyPitch) validate() error {
+	switch p {
+	case "", PitchXLow, PitchLow, PitchMedium, PitchHigh, PitchXHigh, PitchDefault:
+		return nil
+	}
+	for _, unit := range []string{"Hz", "st", "%"} {
+		n, signed, ok, err := parseProsodyNumber(string(p), unit)
+		if !ok {
+			continue
+		}
+		switch {
+		case err != nil:
+			return fmt.Errorf("invalid prosody pitch %q", p)
+		case !signed && unit != "Hz":
+			return fmt.Errorf("prosody pitch %q must be a relative change starting with + or -", p)
+		case !signed && n <= 0:
+			return fmt.Errorf("prosody pitch %q must be above 0Hz", p)
+		case unit == "%" && n <= -100:
+			return fmt.Errorf("prosody pitch %q is out of range, expected more than -100%%", p)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid prosody pitch %q, expected a value in Hz, st or %%", p)
+}
+
+func (v ProsodyVolume) validate() error {
+	switch v {
+	case "", VolumeSilent, VolumeXSoft, VolumeSoft, VolumeMedium, VolumeLoud, VolumeXLoud, VolumeDefault:
+		return nil
+	}
+	n, signed, percent, err := parseProsodyNumber(string(v), "%")
+	if err != nil {
+		return fmt.Errorf("invalid prosody volume %q", v)
+	}
+	if (percent || signed) && (n < -100 || n > 100) {
+		return fmt.Errorf("prosody volume %q is out of range, expected a change from -100 to +100", v)
+	}
+	if !percent && !signed && n > 100 {
+		return fmt.Errorf("prosody volume %q is out of range, expected 0 to 100", v)
+	}
+	return nil
+}
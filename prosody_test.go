@@ -0,0 +1,66 @@
+package azuretexttospeech
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoiceXMLProsody(t *testing.T) {
+	expect := "<speak version='1.0' xml:lang='en-US'><voice xml:lang='en-US' xml:gender='Female' name='en-US-AvaNeural'>" +
+		"<prosody rate='0.8' pitch='+2st' contour='(0%,+20Hz) (50%,-10%)' volume='+50%'>test</prosody></voice></speak>"
+	xml, err := voiceXMLRender(VoiceParam{
+		SpeechText: "test",
+		Voice:      "en-US-AvaNeural",
+		Locale:     LocaleEnUS,
+		Gender:     GenderFemale,
+		Prosody: &Prosody{
+			Rate:    RateMultiplier(0.8),
+			Pitch:   PitchSemitones(2),
+			Volume:  VolumePercent(50),
+			Contour: []ContourPoint{{0, PitchRelativeHz(20)}, {50, PitchPercent(-10)}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, expect, xml)
+}
+
+func TestProsodyValidate(t *testing.T) {
+	valid := []Prosody{
+		{},
+		{Rate: RateXSlow, Pitch: PitchHigh, Volume: VolumeLoud},
+		{Rate: RatePercent(-50), Pitch: PitchHz(600), Volume: VolumeLevel(0)},
+		{Rate: RateMultiplier(2), Pitch: PitchPercent(-50), Volume: VolumeRelative(-20)},
+		{Contour: []ContourPoint{{0, PitchSemitones(-2)}, {100, PitchRelativeHz(30)}}},
+	}
+	for _, p := range valid {
+		assert.NoError(t, p.Validate(), "%+v", p)
+	}
+
+	invalid := []Prosody{
+		{Rate: "quick"},
+		{Rate: RateMultiplier(3)},
+		{Rate: RatePercent(-60)},
+		{Pitch: PitchHz(0)},
+		{Pitch: "2st"},
+		{Pitch: PitchPercent(-100)},
+		{Pitch: "+2dB"},
+		{Volume: VolumeLevel(101)},
+		{Volume: VolumePercent(150)},
+		{Volume: "loudest"},
+		{Rate: "NaN"},
+		{Rate: "NaN%"},
+		{Pitch: "+Infst"},
+		{Volume: "-Infinity%"},
+		{Volume: "NaN"},
+		{Contour: []ContourPoint{{120, PitchSemitones(1)}}},
+		{Contour: []ContourPoint{{50, PitchSemitones(1)}, {10, PitchSemitones(1)}}},
+		{Contour: []ContourPoint{{50, PitchHz(200)}}},
+	}
+	for _, p := range invalid {
+		assert.Error(t, p.Validate(), "%+v", p)
+	}
+
+	_, err := voiceXMLRender(VoiceParam{Prosody: &Prosody{Rate: "quick"}})
+	assert.Error(t, err, "invalid prosody should fail rendering")
+}
@@ -0,0 +1,74 @@
+package azuretexttospeech
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers of the synthesis response which identify the request, most recent first. Quote the request ID when
+// contacting Azure support about a failed or unexpected synthesis.
+var requestIDHeaders = []string{"X-RequestId", "apim-request-id"}
+
+// serverLatencyHeader reports, in milliseconds, the time the service spent processing the request.
+const serverLatencyHeader = "X-Envoy-Upstream-Service-Time"
+
+// SynthesisResult is the audio of a synthesis request with the metadata of the response.
+type SynthesisResult struct {
+	Audio              []byte
+	Format             AudioOutput   // the requested format
+	ContentType        string        // Content-Type of the response
+	RequestID          string        // request ID assigned by Azure, empty when the response has none
+	ServerLatency      time.Duration // processing time reported by the service, zero when not reported
+	FirstByteLatency   time.Duration // time from sending the request to receiving the first byte of the response
+	BillableCharacters int           // see BillableCharacters
+	Duration           time.Duration // playing time of the audio, zero when it cannot be determined from the format
+	Hedged             bool          // the audio was returned by a duplicate request, see Hedger
+}
+
+// newSynthesisResult returns the result of a successful response carrying audio in format.
+func newSynthesisResult(header http.Header, audio []byte, format AudioOutput) *SynthesisResult {
+	result := &SynthesisResult{
+		Audio:       audio,
+		Format:      format,
+		ContentType: header.Get("Content-Type"),
+		Duration:    audioDuration(audio, format),
+	}
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			result.RequestID = id
+			break
+		}
+	}
+	if ms, err := strconv.ParseFloat(header.Get(serverLatencyHeader), 64); err == nil && ms >= 0 {
+		result.ServerLatency = time.Duration(ms * float64(time.Millisecond))
+	}
+	return result
+}
+
+// errWriter remembers the error of a write to w.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// copyAudio copies the response body r to w. Failures to write are reported without their cause, so that a
+// disconnected receiver is not mistaken for a failure of the service, see isServiceFailure.
+func copyAudio(w io.Writer, r io.Reader) (int64, error) {
+	dst := &errWriter{w: w}
+	n, err := io.Copy(dst, r)
+	if dst.err != nil {
+		return n, fmt.Errorf("failed to write audio, %v", dst.err)
+	}
+	return n, err
+}
@@ -0,0 +1,76 @@
+package azuretexttospeech
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/WqyJh/azuretexttospeech/audio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynthesizeResult(t *testing.T) {
+	format, _ := audio.ParseFormat(string(AudioOutput_riff_8khz_16bit_mono_pcm))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/x-wav")
+		w.Header().Set("X-RequestId", "6a4a0b5f3c1d4b8e")
+		w.Header().Set("X-Envoy-Upstream-Service-Time", "120")
+		w.Write(audio.EncodeWAV(format, bytes.Repeat([]byte{0}, 4000)))
+	}))
+	defer ts.Close()
+
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL}
+	result, err := az.SynthesizeResultWithContext(context.Background(), VoiceParam{
+		SpeechText: "Hello",
+		Voice:      "en-US-AvaNeural",
+		Locale:     LocaleEnUS,
+	}, AudioOutput_riff_8khz_16bit_mono_pcm)
+	if assert.NoError(t, err) {
+		assert.Len(t, result.Audio, 4044)
+		assert.Equal(t, AudioOutput_riff_8khz_16bit_mono_pcm, result.Format)
+		assert.Equal(t, "audio/x-wav", result.ContentType)
+		assert.Equal(t, "6a4a0b5f3c1d4b8e", result.RequestID)
+		assert.Equal(t, 120*time.Millisecond, result.ServerLatency)
+		assert.True(t, result.FirstByteLatency > 0)
+		assert.Equal(t, 5, result.BillableCharacters)
+		assert.Equal(t, 250*time.Millisecond, result.Duration)
+	}
+
+	// headers missing from the response leave the fields empty.
+	result = newSynthesisResult(http.Header{"Apim-Request-Id": {"abc"}}, []byte("mp3"), AudioOutput_audio_16khz_32kbitrate_mono_mp3)
+	assert.Equal(t, "abc", result.RequestID)
+	assert.Zero(t, result.ServerLatency)
+	assert.Zero(t, result.Duration)
+
+	// the duration of compressed audio is probed, here 50 MPEG-2 frames of 24 ms.
+	frame := make([]byte, 144)
+	copy(frame, []byte{0xFF, 0xF3, 0x64, 0xC0})
+	result = newSynthesisResult(http.Header{}, bytes.Repeat(frame, 50), AudioOutput_audio_24khz_48kbitrate_mono_mp3)
+	assert.Equal(t, 1200*time.Millisecond, result.Duration)
+}
+
+func TestSynthesizeToWriter(t *testing.T) {
+	format, _ := audio.ParseFormat(string(AudioOutput_riff_8khz_16bit_mono_pcm))
+	wav := audio.EncodeWAV(format, bytes.Repeat([]byte{0}, 8000))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(wav)
+	}))
+	defer ts.Close()
+
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL}
+	var out bytes.Buffer
+	result, err := az.SynthesizeToWriter(context.Background(), VoiceParam{
+		SpeechText: "Hello",
+		Voice:      "en-US-AvaNeural",
+		Locale:     LocaleEnUS,
+	}, AudioOutput_riff_8khz_16bit_mono_pcm, &out)
+	if assert.NoError(t, err) {
+		assert.Equal(t, wav, out.Bytes())
+		assert.Nil(t, result.Audio)
+		assert.Equal(t, 500*time.Millisecond, result.Duration)
+		assert.Equal(t, 5, result.BillableCharacters)
+	}
+}
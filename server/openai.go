@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	tts "github.com/WqyJh/azuretexttospeech"
+)
+
+// The speaking rates supported by the Speech service. OpenAI speeds outside this range are clamped.
+const (
+	minSpeed = 0.5
+	maxSpeed = 2
+)
+
+// OpenAISpeechRequest is the body of `POST /v1/audio/speech` of the OpenAI API.
+// See: https://platform.openai.com/docs/api-reference/audio/createSpeech
+type OpenAISpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format,omitempty"`
+	Speed          float64 `json:"speed,omitempty"`
+}
+
+// OpenAIMapping translates OpenAI voices and response formats to Azure. Voices missing from Voices are looked
+// up in the voice list of the region, so clients can also pick any Azure voice, such as "en-US-AvaNeural". The
+// model of a request is ignored.
+type OpenAIMapping struct {
+	// Voices maps OpenAI voice names to the Voice, Locale, Gender and Style of a VoiceParam.
+	Voices map[string]tts.VoiceParam
+	// Formats maps the response_format of a request to an AudioOutput, "mp3" is the default.
+	Formats map[string]tts.AudioOutput
+}
+
+// DefaultOpenAIMapping maps the OpenAI voices to Azure neural voices of a similar character, and the response
+// formats to 24 kHz Azure formats. "aac" and "flac" have no Azure equivalent.
+var DefaultOpenAIMapping = OpenAIMapping{
+	Voices: map[string]tts.VoiceParam{
+		"alloy":   {Voice: "en-US-AvaMultilingualNeural", Locale: tts.LocaleEnUS, Gender: tts.GenderFemale},
+		"ash":     {Voice: "en-US-GuyNeural", Locale: tts.LocaleEnUS, Gender: tts.GenderMale},
+		"ballad":  {Voice: "en-US-DavisNeural", Locale: tts.LocaleEnUS, Gender: tts.GenderMale},
+		"coral":   {Voice: "en-US-AriaNeural", Locale: tts.LocaleEnUS, Gender: tts.GenderFemale},
+		"echo":    {Voice: "en-US-AndrewMultilingualNeural", Locale: tts.LocaleEnUS, Gender: tts.GenderMale},
+		"fable":   {Voice: "en-GB-RyanNeural", Locale: tts.LocaleEnGB, Gender: tts.GenderMale},
+		"nova":    {Voice: "en-US-EmmaMultilingualNeural", Locale: tts.LocaleEnUS, Gender: tts.GenderFemale},
+		"onyx":    {Voice: "en-US-BrianMultilingualNeural", Locale: tts.LocaleEnUS, Gender: tts.GenderMale},
+		"sage":    {Voice: "en-US-SaraNeural", Locale: tts.LocaleEnUS, Gender: tts.GenderFemale},
+		"shimmer": {Voice: "en-US-JennyNeural", Locale: tts.LocaleEnUS, Gender: tts.GenderFemale},
+		"verse":   {Voice: "en-US-TonyNeural", Locale: tts.LocaleEnUS, Gender: tts.GenderMale},
+	},
+	Formats: map[string]tts.AudioOutput{
+		"mp3":  tts.AudioOutput_audio_24khz_48kbitrate_mono_mp3,
+		"opus": tts.AudioOutput_ogg_24khz_16bit_mono_opus,
+		"wav":  tts.AudioOutput_riff_24khz_16bit_mono_pcm,
+		"pcm":  tts.AudioOutput_raw_24khz_16bit_mono_pcm, // 24 kHz 16 bit little-endian, as returned by OpenAI
+	},
+}
+
+// openAIError is the error body of the OpenAI API.
+type openAIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+func writeOpenAIError(w http.ResponseWriter, status int, err error) {
+	var e openAIError
+	e.Error.Message = err.Error()
+	e.Error.Type = "invalid_request_error"
+	if status >= http.StatusInternalServerError {
+		e.Error.Type = "server_error"
+	}
+	writeJSON(w, status, e)
+}
+
+// openAIParam converts req to the parameters of the client.
+func (s *Server) openAIParam(ctx context.Context, req OpenAISpeechRequest) (tts.VoiceParam, tts.AudioOutput, error) {
+	name := req.ResponseFormat
+	if name == "" {
+		name = "mp3"
+	}
+	format, ok := s.openAI.Formats[name]
+	if !ok {
+		return tts.VoiceParam{}, "", fmt.Errorf("%w, unsupported response_format %q", tts.ErrInvalidRequest, name)
+	}
+	if req.Input == "" || req.Voice == "" {
+		return tts.VoiceParam{}, "", fmt.Errorf("%w, input and voice are required", tts.ErrInvalidRequest)
+	}
+
+	var param tts.VoiceParam
+	if mapped, ok := s.openAI.Voices[req.Voice]; ok {
+		var text strings.Builder
+		xml.EscapeText(&text, []byte(req.Input))
+		param = mapped
+		param.SpeechText = text.String()
+	} else {
+		// not an OpenAI voice, try an Azure voice of the region.
+		var err error
+		if param, err = s.voiceParam(ctx, SynthesizeRequest{Text: req.Input, Voice: req.Voice}); err != nil {
+			return tts.VoiceParam{}, "", err
+		}
+	}
+
+	if req.Speed != 0 && req.Speed != 1 {
+		var prosody tts.Prosody
+		if param.Prosody != nil {
+			prosody = *param.Prosody
+		}
+		prosody.Rate = tts.RateMultiplier(min(max(req.Speed, minSpeed), maxSpeed))
+		param.Prosody = &prosody
+	}
+	return param, format, nil
+}
+
+func (s *Server) handleOpenAISpeech(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if tenant := r.Header.Get(TenantHeader); tenant != "" {
+		ctx = tts.WithTenant(ctx, tenant)
+	}
+	var req OpenAISpeechRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxBodyBytes)).Decode(&req); err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, fmt.Errorf("invalid request body, %v", err))
+		return
+	}
+	param, format, err := s.openAIParam(ctx, req)
+	if err != nil {
+		writeOpenAIError(w, statusOf(err), err)
+		return
+	}
+	s.streamAudio(ctx, w, format, writeOpenAIError, func(out io.Writer) (*tts.SynthesisResult, error) {
+		return s.az.SynthesizeToWriter(ctx, param, format, out)
+	})
+}
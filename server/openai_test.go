@@ -0,0 +1,52 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAISpeech(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := http.Post(ts.URL+"/v1/audio/speech", "application/json",
+		strings.NewReader(`{"model": "tts-1", "input": "Fish & Chips", "voice": "alloy", "response_format": "wav", "speed": 4}`))
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "audio/wav", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "riff-24khz-16bit-mono-pcm|<speak version='1.0' xml:lang='en-US'><voice xml:lang='en-US' xml:gender='Female' name='en-US-AvaMultilingualNeural'><prosody rate='2'>Fish &amp; Chips</prosody></voice></speak>", string(body))
+	}
+
+	resp, err = http.Post(ts.URL+"/v1/audio/speech", "application/json",
+		strings.NewReader(`{"model": "tts-1", "input": "Hello", "voice": "en-US-AvaNeural"}`))
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "audio/mpeg", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "audio-24khz-48kbitrate-mono-mp3|<speak version='1.0' xml:lang='en-US'><voice xml:lang='en-US' xml:gender='Female' name='en-US-AvaNeural'>Hello</voice></speak>", string(body))
+	}
+
+	for body, status := range map[string]int{
+		`{"model": "tts-1", "voice": "alloy"}`:                                              http.StatusBadRequest,
+		`{"model": "tts-1", "input": "Hello", "voice": "bob"}`:                              http.StatusBadRequest,
+		`{"model": "tts-1", "input": "Hello", "voice": "alloy", "response_format": "flac"}`: http.StatusBadRequest,
+		`{"model": "tts-1", "input": "unavailable", "voice": "alloy"}`:                      http.StatusBadGateway,
+	} {
+		resp, err := http.Post(ts.URL+"/v1/audio/speech", "application/json", strings.NewReader(body))
+		if assert.NoError(t, err) {
+			var e openAIError
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&e))
+			resp.Body.Close()
+			assert.Equal(t, status, resp.StatusCode, body)
+			assert.NotEmpty(t, e.Error.Message)
+			assert.NotEmpty(t, e.Error.Type)
+		}
+	}
+}
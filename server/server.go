@@ -0,0 +1,368 @@
+// Package server exposes an AzureCSTextToSpeech client as a local HTTP service, so that programs in other
+// languages can use its token refresh, normalization, quotas and resilience options.
+//
+// The client has neither an audio cache nor a request rate limiter, so the server sends every synthesis
+// request to the Speech service. Limit the characters of each tenant with tts.WithQuota and shed load while
+// the service fails with tts.WithCircuitBreaker; caching and rate limiting are left to a proxy in front of the
+// server.
+//
+// Routes:
+//
+//	POST /synthesize       JSON (SynthesizeRequest) or SSML (Content-Type application/ssml+xml) in, audio out
+//	GET  /voices           voices of the region, optionally filtered by ?locale=
+//	GET  /formats          supported audio formats and their content types
+//	GET  /healthz          credential and voice list check, see AzureCSTextToSpeech.Verify
+//	POST /v1/audio/speech  the speech endpoint of the OpenAI API, see OpenAIMapping
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	tts "github.com/WqyJh/azuretexttospeech"
+)
+
+// Defaults of a Server.
+const (
+	defaultFormat       = tts.AudioOutput_audio_24khz_48kbitrate_mono_mp3
+	defaultMaxBodyBytes = 64 * 1024
+	healthCacheTTL      = 30 * time.Second
+	healthTimeout       = 10 * time.Second
+)
+
+// TenantHeader attributes a synthesis request to a tenant, see tts.WithTenant.
+const TenantHeader = "X-Tenant"
+
+// SynthesizeRequest is the JSON body of `POST /synthesize`. Text is plain text, it is escaped for SSML. Locale
+// and Gender default to those of the voice.
+type SynthesizeRequest struct {
+	Text        string  `json:"text"`
+	Voice       string  `json:"voice"`
+	Locale      string  `json:"locale,omitempty"`
+	Gender      string  `json:"gender,omitempty"`
+	Style       string  `json:"style,omitempty"`
+	StyleDegree float64 `json:"style_degree,omitempty"`
+	Role        string  `json:"role,omitempty"`
+	Format      string  `json:"format,omitempty"`
+}
+
+// Format describes an audio format in the response of `GET /formats`.
+type Format struct {
+	Name        tts.AudioOutput `json:"name"`
+	ContentType string          `json:"content_type"`
+}
+
+// errorResponse is the body of failed requests.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server serves synthesis requests with a single client.
+type Server struct {
+	az            *tts.AzureCSTextToSpeech
+	mux           *http.ServeMux
+	defaultFormat tts.AudioOutput
+	maxBodyBytes  int64
+	healthVoices  []string
+	openAI        OpenAIMapping
+	log           *slog.Logger
+
+	healthMu sync.Mutex
+	health   *tts.VerifyReport
+	healthAt time.Time
+}
+
+// Option configures a Server, see New.
+type Option func(*Server)
+
+// WithDefaultFormat sets the format of requests which do not name one. The default is
+// AudioOutput_audio_24khz_48kbitrate_mono_mp3.
+func WithDefaultFormat(f tts.AudioOutput) Option {
+	return func(s *Server) {
+		s.defaultFormat = f
+	}
+}
+
+// WithMaxBodyBytes limits the size of synthesis requests. The default is 64 KiB, the request limit of the
+// Speech service.
+func WithMaxBodyBytes(n int64) Option {
+	return func(s *Server) {
+		s.maxBodyBytes = n
+	}
+}
+
+// WithHealthVoices makes `/healthz` fail unless voices are available in the region.
+func WithHealthVoices(voices ...string) Option {
+	return func(s *Server) {
+		s.healthVoices = voices
+	}
+}
+
+// WithOpenAIMapping translates requests to `/v1/audio/speech` with m instead of DefaultOpenAIMapping.
+func WithOpenAIMapping(m OpenAIMapping) Option {
+	return func(s *Server) {
+		s.openAI = m
+	}
+}
+
+// WithLogger logs failed requests to logger instead of slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		s.log = logger
+	}
+}
+
+// New returns a Server synthesizing with az.
+func New(az *tts.AzureCSTextToSpeech, opts ...Option) *Server {
+	s := &Server{
+		az:            az,
+		mux:           http.NewServeMux(),
+		defaultFormat: defaultFormat,
+		maxBodyBytes:  defaultMaxBodyBytes,
+		openAI:        DefaultOpenAIMapping,
+		log:           slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux.HandleFunc("POST /synthesize", s.handleSynthesize)
+	s.mux.HandleFunc("GET /voices", s.handleVoices)
+	s.mux.HandleFunc("GET /formats", s.handleFormats)
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("POST /v1/audio/speech", s.handleOpenAISpeech)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// parseFormat returns the format named name, or the default format when name is empty.
+func (s *Server) parseFormat(name string) (tts.AudioOutput, error) {
+	if name == "" {
+		return s.defaultFormat, nil
+	}
+	for _, f := range tts.AudioOutputs {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported format %q, see /formats", name)
+}
+
+// voiceParam converts req to the parameters of the client, looking up the locale and gender of the voice
+// when they are not given.
+func (s *Server) voiceParam(ctx context.Context, req SynthesizeRequest) (tts.VoiceParam, error) {
+	if req.Text == "" || req.Voice == "" {
+		return tts.VoiceParam{}, fmt.Errorf("%w, text and voice are required", tts.ErrInvalidRequest)
+	}
+	var text strings.Builder
+	xml.EscapeText(&text, []byte(req.Text))
+	param := tts.VoiceParam{
+		SpeechText:  text.String(),
+		Voice:       req.Voice,
+		Locale:      tts.Locale(req.Locale),
+		Style:       req.Style,
+		StyleDegree: req.StyleDegree,
+		Role:        req.Role,
+	}
+	if req.Gender != "" {
+		gender, err := tts.GenderString(req.Gender)
+		if err != nil {
+			return tts.VoiceParam{}, fmt.Errorf("%w, %v", tts.ErrInvalidRequest, err)
+		}
+		param.Gender = gender
+	}
+	if req.Locale != "" && req.Gender != "" {
+		return param, nil
+	}
+
+	v, err := s.az.LookupVoice(ctx, req.Voice)
+	if err != nil {
+		return tts.VoiceParam{}, err
+	}
+	if req.Locale == "" {
+		param.Locale = v.Locale
+	}
+	if req.Gender == "" {
+		param.Gender = v.Gender
+	}
+	return param, nil
+}
+
+// flushWriter sends every write to the client immediately, using chunked encoding.
+type flushWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	written bool
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	f.written = true
+	n, err := f.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, f.rc.Flush()
+}
+
+func (s *Server) handleSynthesize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if tenant := r.Header.Get(TenantHeader); tenant != "" {
+		ctx = tts.WithTenant(ctx, tenant)
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, err)
+		} else {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unable to read request body, %v", err))
+		}
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	ssml := mediaType == "application/ssml+xml"
+	formatName := r.URL.Query().Get("format")
+	var param tts.VoiceParam
+	if !ssml {
+		var req SynthesizeRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body, %v", err))
+			return
+		}
+		if param, err = s.voiceParam(ctx, req); err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		if req.Format != "" {
+			formatName = req.Format
+		}
+	}
+	format, err := s.parseFormat(formatName)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.streamAudio(ctx, w, format, writeError, func(out io.Writer) (*tts.SynthesisResult, error) {
+		if ssml {
+			return s.az.SynthesizeSSMLToWriter(ctx, string(body), format, out)
+		}
+		return s.az.SynthesizeToWriter(ctx, param, format, out)
+	})
+}
+
+// streamAudio sends the audio written by synthesize as it arrives. Failures before the first write are
+// reported with fail, later failures abort the response. The request ID and billable characters of the
+// synthesis are sent as trailers.
+func (s *Server) streamAudio(ctx context.Context, w http.ResponseWriter, format tts.AudioOutput, fail func(http.ResponseWriter, int, error), synthesize func(io.Writer) (*tts.SynthesisResult, error)) {
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Trailer", "X-Request-Id, X-Billable-Characters")
+	out := &flushWriter{w: w, rc: http.NewResponseController(w)}
+	result, err := synthesize(out)
+	if err != nil {
+		s.log.LogAttrs(ctx, slog.LevelWarn, "synthesis failed", slog.Any("error", err))
+		if out.written {
+			// the status is sent already, abort so that the client does not take partial audio as complete.
+			panic(http.ErrAbortHandler)
+		}
+		w.Header().Del("Trailer")
+		fail(w, statusOf(err), err)
+		return
+	}
+	w.Header().Set("X-Request-Id", result.RequestID)
+	w.Header().Set("X-Billable-Characters", fmt.Sprint(result.BillableCharacters))
+}
+
+// statusOf returns the HTTP status reporting err to the client.
+func statusOf(err error) int {
+	var statusErr *tts.StatusError
+	switch {
+	case errors.Is(err, tts.ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, tts.ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	case errors.Is(err, tts.ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.As(err, &statusErr):
+		if statusErr.StatusCode == http.StatusBadRequest || statusErr.StatusCode == http.StatusTooManyRequests {
+			return statusErr.StatusCode
+		}
+		// authorization and server errors of the Speech service are not the fault of the client.
+		return http.StatusBadGateway
+	}
+	// the remaining errors occur on the way to the Speech service, such as a failed voice list or token request.
+	return http.StatusBadGateway
+}
+
+func (s *Server) handleVoices(w http.ResponseWriter, r *http.Request) {
+	voices, err := s.az.Voices(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if locale := r.URL.Query().Get("locale"); locale != "" {
+		filtered := []tts.Voice{}
+		for _, v := range voices {
+			if strings.EqualFold(string(v.Locale), locale) {
+				filtered = append(filtered, v)
+			}
+		}
+		voices = filtered
+	}
+	writeJSON(w, http.StatusOK, voices)
+}
+
+func (s *Server) handleFormats(w http.ResponseWriter, r *http.Request) {
+	formats := make([]Format, len(tts.AudioOutputs))
+	for i, f := range tts.AudioOutputs {
+		formats[i] = Format{Name: f, ContentType: f.ContentType()}
+	}
+	writeJSON(w, http.StatusOK, formats)
+}
+
+// handleHealth reports the result of Verify, which is cached for healthCacheTTL so that frequent probes do not
+// hammer the Speech service. Verify does not use the context of the request, so a prober disconnecting early
+// cannot cache a canceled report.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.healthMu.Lock()
+	if s.health == nil || time.Since(s.healthAt) > healthCacheTTL {
+		ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+		s.health = s.az.Verify(ctx, s.healthVoices...)
+		s.healthAt = time.Now()
+		cancel()
+	}
+	report := s.health
+	s.healthMu.Unlock()
+
+	status := http.StatusOK
+	if !report.OK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tts "github.com/WqyJh/azuretexttospeech"
+	"github.com/stretchr/testify/assert"
+)
+
+const voiceList = `[
+  {"Name": "Microsoft Server Speech Text to Speech Voice (en-US, AvaNeural)", "ShortName": "en-US-AvaNeural", "Gender": "Female", "Locale": "en-US", "VoiceType": "Neural"},
+  {"Name": "Microsoft Server Speech Text to Speech Voice (de-DE, ConradNeural)", "ShortName": "de-DE-ConradNeural", "Gender": "Male", "Locale": "de-DE", "VoiceType": "Neural"}
+]`
+
+// newTestServer returns a Server backed by a fake Speech service, see newTestHandler.
+func newTestServer(t *testing.T) *httptest.Server {
+	ts := httptest.NewServer(newTestHandler(t))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// newTestHandler returns a Server backed by a fake Speech service, which answers with the SSML it received
+// and fails documents containing "unavailable".
+func newTestHandler(t *testing.T) *Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("SYS49152"))
+	})
+	mux.HandleFunc("/voices", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(voiceList))
+	})
+	mux.HandleFunc("/tts", func(w http.ResponseWriter, r *http.Request) {
+		ssml, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(ssml), "unavailable") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-RequestId", "6a4a0b5f")
+		w.Write([]byte(r.Header.Get("X-Microsoft-OutputFormat") + "|"))
+		w.Write(ssml)
+	})
+	azure := httptest.NewServer(mux)
+	t.Cleanup(azure.Close)
+
+	az, err := tts.New("SYS64738", tts.RegionEastUS, tts.WithEndpoints(tts.Endpoints{
+		TextToSpeech: azure.URL + "/tts",
+		TokenRefresh: azure.URL + "/token",
+		VoiceList:    azure.URL + "/voices",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { close(az.TokenRefreshDoneCh) })
+	return New(az, WithHealthVoices("en-US-AvaNeural"))
+}
+
+func TestSynthesize(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := http.Post(ts.URL+"/synthesize", "application/json",
+		strings.NewReader(`{"text": "Fish & Chips", "voice": "de-DE-ConradNeural", "format": "riff-24khz-16bit-mono-pcm"}`))
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "audio/wav", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "riff-24khz-16bit-mono-pcm|<speak version='1.0' xml:lang='de-DE'><voice xml:lang='de-DE' xml:gender='Male' name='de-DE-ConradNeural'>Fish &amp; Chips</voice></speak>", string(body))
+		assert.Equal(t, "6a4a0b5f", resp.Trailer.Get("X-Request-Id"))
+		assert.Equal(t, "16", resp.Trailer.Get("X-Billable-Characters"))
+	}
+
+	ssml := "<speak version='1.0' xml:lang='en-US'><voice name='en-US-AvaNeural'>Hello</voice></speak>"
+	resp, err = http.Post(ts.URL+"/synthesize?format=ogg-24khz-16bit-mono-opus", "application/ssml+xml", strings.NewReader(ssml))
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "audio/ogg", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "ogg-24khz-16bit-mono-opus|"+ssml, string(body))
+	}
+
+	for body, status := range map[string]int{
+		`{"text": "Hello"}`:                                              http.StatusBadRequest,
+		`{"text": "Hello", "voice": "xx-XX-Nobody"}`:                     http.StatusBadRequest,
+		`{"text": "Hello", "voice": "en-US-AvaNeural", "format": "wav"}`: http.StatusBadRequest,
+		`{"text": "unavailable", "voice": "en-US-AvaNeural"}`:            http.StatusBadGateway,
+	} {
+		resp, err := http.Post(ts.URL+"/synthesize", "application/json", strings.NewReader(body))
+		if assert.NoError(t, err) {
+			var e errorResponse
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&e))
+			resp.Body.Close()
+			assert.Equal(t, status, resp.StatusCode, body)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			assert.NotEmpty(t, e.Error)
+		}
+	}
+}
+
+func TestVoicesFormatsHealth(t *testing.T) {
+	ts := newTestServer(t)
+
+	var voices []tts.Voice
+	resp, err := http.Get(ts.URL + "/voices?locale=de-de")
+	if assert.NoError(t, err) {
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&voices))
+		resp.Body.Close()
+		if assert.Len(t, voices, 1) {
+			assert.Equal(t, "de-DE-ConradNeural", voices[0].ShortName)
+		}
+	}
+
+	var formats []Format
+	resp, err = http.Get(ts.URL + "/formats")
+	if assert.NoError(t, err) {
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&formats))
+		resp.Body.Close()
+		assert.Len(t, formats, len(tts.AudioOutputs))
+		assert.Contains(t, formats, Format{Name: tts.AudioOutput_raw_8khz_8bit_mono_mulaw, ContentType: "audio/PCMU"})
+	}
+
+	var report tts.VerifyReport
+	resp, err = http.Get(ts.URL + "/healthz")
+	if assert.NoError(t, err) {
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, report.OK)
+		assert.Len(t, report.Checks, 3)
+	}
+}
+
+func TestHealthIgnoresRequestContext(t *testing.T) {
+	s := newTestHandler(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil).WithContext(ctx))
+	assert.Equal(t, http.StatusOK, w.Code, "a disconnected prober does not cache a failed report")
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestSynthesizeBodyErrors(t *testing.T) {
+	s := newTestHandler(t)
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/synthesize", failingReader{}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/synthesize", strings.NewReader(strings.Repeat("a", defaultMaxBodyBytes+1))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestStatusOf(t *testing.T) {
+	for err, want := range map[error]int{
+		fmt.Errorf("%w, text and voice are required", tts.ErrInvalidRequest): http.StatusBadRequest,
+		fmt.Errorf("failed, %w", tts.ErrQuotaExceeded):                       http.StatusTooManyRequests,
+		tts.ErrCircuitOpen:                                           http.StatusServiceUnavailable,
+		context.DeadlineExceeded:                                     http.StatusGatewayTimeout,
+		&tts.StatusError{StatusCode: http.StatusBadRequest}:          http.StatusBadRequest,
+		&tts.StatusError{StatusCode: http.StatusUnauthorized}:        http.StatusBadGateway,
+		errors.New("failed to fetch voice list, connection refused"): http.StatusBadGateway,
+	} {
+		assert.Equal(t, want, statusOf(err), err.Error())
+	}
+}
@@ -0,0 +1,585 @@
+package azuretexttospeech
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ssmlNamespace  = "http://www.w3.org/2001/10/synthesis"
+	msttsNamespace = "https://www.w3.org/2001/mstts"
+	xmlNamespace   = "http://www.w3.org/XML/1998/namespace"
+)
+
+// SSMLNode is a node of a parsed SSML document, either an *SSMLElement or an *SSMLText.
+type SSMLNode interface {
+	// Position returns the line and column at which the node starts in the source document.
+	Position() (line, column int)
+	writeTo(b *strings.Builder)
+}
+
+// SSMLAttr is an attribute of an SSMLElement. Names are qualified with their conventional prefix, such as
+// "xml:lang" or "xmlns:mstts".
+type SSMLAttr struct {
+	Name  string
+	Value string
+}
+
+// SSMLElement is an element of an SSML document. Name is qualified with its prefix for elements of the
+// mstts namespace, "mstts:express-as".
+type SSMLElement struct {
+	Name         string
+	Attrs        []SSMLAttr
+	Children     []SSMLNode
+	Line, Column int
+}
+
+// SSMLText is character data of an SSML document, unescaped.
+type SSMLText struct {
+	Text         string
+	Line, Column int
+}
+
+// SSMLDocument is a parsed SSML document, see ParseSSML.
+type SSMLDocument struct {
+	Root *SSMLElement
+}
+
+// Position implements SSMLNode.
+func (e *SSMLElement) Position() (int, int) { return e.Line, e.Column }
+
+// Position implements SSMLNode.
+func (t *SSMLText) Position() (int, int) { return t.Line, t.Column }
+
+// Attr returns the value of the attribute called name and whether it is present.
+func (e *SSMLElement) Attr(name string) (string, bool) {
+	for _, a := range e.Attrs {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+func (e *SSMLElement) writeTo(b *strings.Builder) {
+	b.WriteString("<" + e.Name)
+	for _, a := range e.Attrs {
+		fmt.Fprintf(b, " %s='%s'", a.Name, xmlAttrQuoted(a.Value))
+	}
+	if len(e.Children) == 0 {
+		b.WriteString("/>")
+		return
+	}
+	b.WriteString(">")
+	for _, c := range e.Children {
+		c.writeTo(b)
+	}
+	b.WriteString("</" + e.Name + ">")
+}
+
+func (t *SSMLText) writeTo(b *strings.Builder) {
+	b.WriteString(xmlTextEscaper.Replace(t.Text))
+}
+
+// String renders the document as SSML.
+func (d *SSMLDocument) String() string {
+	var b strings.Builder
+	d.Root.writeTo(&b)
+	return b.String()
+}
+
+// xmlAttrQuoted escapes s for use in a single quoted attribute.
+func xmlAttrQuoted(s string) string {
+	return strings.ReplaceAll(xmlAttr(s), "'", "&#39;")
+}
+
+// SSMLError is a problem found at a position of an SSML document.
+type SSMLError struct {
+	Line, Column int
+	Message      string
+}
+
+func (e *SSMLError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// SSMLErrors lists every problem found while validating an SSML document.
+type SSMLErrors []*SSMLError
+
+func (errs SSMLErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// SSMLOption configures the validation of ParseSSML.
+type SSMLOption func(*ssmlLimits)
+
+type ssmlLimits struct {
+	maxCharacters int
+}
+
+// MaxBillableCharacters rejects documents of more than n billable characters, see BillableCharacters.
+// Documents are only limited by their size by default.
+func MaxBillableCharacters(n int) SSMLOption {
+	return func(l *ssmlLimits) {
+		l.maxCharacters = n
+	}
+}
+
+// ParseSSML parses doc and validates it against the subset of SSML accepted by the Speech service. Syntax
+// errors are returned as an *SSMLError and validation failures as SSMLErrors. Use VoiceParams to convert the
+// document into the parameters of the synthesis methods.
+// See: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/speech-synthesis-markup-structure
+func ParseSSML(doc string, opts ...SSMLOption) (*SSMLDocument, error) {
+	var limits ssmlLimits
+	for _, opt := range opts {
+		opt(&limits)
+	}
+	if len(doc) > maxSSMLSize {
+		return nil, &SSMLError{Line: 1, Column: 1, Message: fmt.Sprintf("document is %d bytes, exceeding the %d byte limit", len(doc), maxSSMLSize)}
+	}
+
+	d := xml.NewDecoder(strings.NewReader(doc))
+	var root *SSMLElement
+	var stack []*SSMLElement
+	for {
+		line, column := d.InputPos()
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			line, column = d.InputPos()
+			if se, ok := err.(*xml.SyntaxError); ok {
+				return nil, &SSMLError{Line: se.Line, Column: column, Message: se.Msg}
+			}
+			return nil, &SSMLError{Line: line, Column: column, Message: err.Error()}
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			e := &SSMLElement{Name: ssmlName(t.Name), Line: line, Column: column}
+			for _, a := range t.Attr {
+				e.Attrs = append(e.Attrs, SSMLAttr{Name: ssmlName(a.Name), Value: a.Value})
+			}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, &SSMLError{Line: line, Column: column, Message: "document has more than one root element"}
+				}
+				root = e
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, e)
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) == 0 {
+				if strings.TrimSpace(string(t)) != "" {
+					return nil, &SSMLError{Line: line, Column: column, Message: "text outside of the root element"}
+				}
+				continue
+			}
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, &SSMLText{Text: string(t), Line: line, Column: column})
+		}
+	}
+	if root == nil {
+		return nil, &SSMLError{Line: 1, Column: 1, Message: "document has no root element"}
+	}
+
+	document := &SSMLDocument{Root: root}
+	err := document.Validate()
+	if limits.maxCharacters > 0 {
+		// the document parsed, so it can be counted.
+		if n, _ := BillableCharacters(doc); n > limits.maxCharacters {
+			errs, _ := err.(SSMLErrors)
+			err = append(errs, &SSMLError{Line: root.Line, Column: root.Column,
+				Message: fmt.Sprintf("document has %d billable characters, exceeding the limit of %d", n, limits.maxCharacters)})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// ssmlName qualifies a name resolved by the XML decoder with its conventional prefix.
+func ssmlName(n xml.Name) string {
+	switch n.Space {
+	case "", ssmlNamespace:
+		return n.Local
+	case xmlNamespace, "xml":
+		return "xml:" + n.Local
+	case msttsNamespace, "mstts":
+		return "mstts:" + n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+// ssmlRule describes the attributes and children an element accepts.
+type ssmlRule struct {
+	required []string
+	optional []string
+	children map[string]bool // allowed child elements, "#text" allows text
+	empty    bool            // the element has no content
+}
+
+func ssmlSet(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}
+
+var (
+	ssmlInline = []string{"#text", "prosody", "break", "sub", "phoneme", "say-as", "emphasis", "audio", "bookmark",
+		"lang", "p", "s", "mstts:express-as", "mstts:silence", "mstts:viseme", "mstts:audioduration"}
+	ssmlVoiceContent = append([]string{"lexicon"}, ssmlInline...)
+)
+
+var ssmlRules = map[string]ssmlRule{
+	"speak":                 {required: []string{"version", "xml:lang"}, optional: []string{"xmlns", "xmlns:mstts", "xmlns:emo"}, children: ssmlSet("voice", "mstts:backgroundaudio")},
+	"voice":                 {required: []string{"name"}, optional: []string{"xml:lang", "xml:gender", "effect"}, children: ssmlSet(ssmlVoiceContent...)},
+	"mstts:express-as":      {optional: []string{"style", "styledegree", "role"}, children: ssmlSet(ssmlInline...)},
+	"prosody":               {optional: []string{"rate", "pitch", "contour", "range", "volume"}, children: ssmlSet(ssmlInline...)},
+	"p":                     {children: ssmlSet(ssmlInline...)},
+	"s":                     {children: ssmlSet(ssmlInline...)},
+	"lang":                  {required: []string{"xml:lang"}, children: ssmlSet(ssmlInline...)},
+	"emphasis":              {optional: []string{"level"}, children: ssmlSet("#text", "break", "sub", "phoneme", "say-as", "bookmark")},
+	"sub":                   {required: []string{"alias"}, children: ssmlSet("#text")},
+	"phoneme":               {required: []string{"ph"}, optional: []string{"alphabet"}, children: ssmlSet("#text")},
+	"say-as":                {required: []string{"interpret-as"}, optional: []string{"format", "detail"}, children: ssmlSet("#text")},
+	"audio":                 {required: []string{"src"}, children: ssmlSet("#text")},
+	"break":                 {optional: []string{"strength", "time"}, empty: true},
+	"bookmark":              {required: []string{"mark"}, empty: true},
+	"lexicon":               {required: []string{"uri"}, empty: true},
+	"mstts:silence":         {required: []string{"type", "value"}, empty: true},
+	"mstts:viseme":          {required: []string{"type"}, empty: true},
+	"mstts:audioduration":   {required: []string{"value"}, empty: true},
+	"mstts:backgroundaudio": {required: []string{"src"}, optional: []string{"volume", "fadein", "fadeout"}, empty: true},
+}
+
+var breakTimePattern = regexp.MustCompile(`^(\d+)(ms|s)$`)
+
+// ssmlValidator collects the errors found while walking a document.
+type ssmlValidator struct {
+	errs   SSMLErrors
+	voices int
+	mstts  bool // the document uses elements of the mstts namespace
+}
+
+func (v *ssmlValidator) errorf(n SSMLNode, format string, args ...interface{}) {
+	line, column := n.Position()
+	v.errs = append(v.errs, &SSMLError{Line: line, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks the element nesting, attributes and request limits of the document.
+func (d *SSMLDocument) Validate() error {
+	v := &ssmlValidator{}
+	if d.Root.Name != "speak" {
+		v.errorf(d.Root, "root element must be speak, got %s", d.Root.Name)
+		return v.errs
+	}
+	v.element(d.Root)
+	if v.voices == 0 {
+		v.errorf(d.Root, "document has no voice element")
+	}
+	if v.voices > maxVoiceElements {
+		v.errorf(d.Root, "document has %d voice elements, exceeding the limit of %d", v.voices, maxVoiceElements)
+	}
+	if _, ok := d.Root.Attr("xmlns:mstts"); v.mstts && !ok {
+		v.errorf(d.Root, "mstts elements require the xmlns:mstts='%s' declaration", msttsNamespace)
+	}
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+func (v *ssmlValidator) element(e *SSMLElement) {
+	rule, ok := ssmlRules[e.Name]
+	if !ok {
+		v.errorf(e, "unsupported element %s", e.Name)
+		return
+	}
+	if e.Name == "voice" {
+		v.voices++
+	}
+	if strings.HasPrefix(e.Name, "mstts:") {
+		v.mstts = true
+	}
+
+	for _, name := range rule.required {
+		if _, ok := e.Attr(name); !ok {
+			v.errorf(e, "%s requires the %s attribute", e.Name, name)
+		}
+	}
+	for _, a := range e.Attrs {
+		if !slices.Contains(rule.required, a.Name) && !slices.Contains(rule.optional, a.Name) {
+			v.errorf(e, "%s does not accept the %s attribute", e.Name, a.Name)
+			continue
+		}
+		if err := validateSSMLAttr(e.Name, a); err != nil {
+			v.errorf(e, "%s", err)
+		}
+	}
+
+	for _, c := range e.Children {
+		switch c := c.(type) {
+		case *SSMLText:
+			if strings.TrimSpace(c.Text) == "" {
+				continue
+			}
+			if rule.empty || !rule.children["#text"] {
+				v.errorf(c, "%s cannot contain text", e.Name)
+			}
+		case *SSMLElement:
+			if rule.empty || !rule.children[c.Name] {
+				if _, known := ssmlRules[c.Name]; known {
+					v.errorf(c, "%s is not allowed inside %s", c.Name, e.Name)
+					continue
+				}
+			}
+			v.element(c)
+		}
+	}
+}
+
+// validateSSMLAttr checks attribute values which have a fixed syntax.
+func validateSSMLAttr(element string, a SSMLAttr) error {
+	switch element + "/" + a.Name {
+	case "prosody/rate":
+		return ProsodyRate(a.Value).validate()
+	case "prosody/pitch":
+		return ProsodyPitch(a.Value).validate()
+	case "prosody/volume":
+		return ProsodyVolume(a.Value).validate()
+	case "mstts:express-as/styledegree":
+		f, err := strconv.ParseFloat(a.Value, 64)
+		if err != nil || f < 0.01 || f > 2 {
+			return fmt.Errorf("styledegree %q is out of range, expected a value from 0.01 to 2", a.Value)
+		}
+	case "phoneme/alphabet":
+		if !PhoneticAlphabet(a.Value).IsValid() {
+			return fmt.Errorf("unsupported phonetic alphabet %q", a.Value)
+		}
+	case "break/time":
+		m := breakTimePattern.FindStringSubmatch(a.Value)
+		if m == nil {
+			return fmt.Errorf("break time %q must be a duration in ms or s", a.Value)
+		}
+		n, _ := strconv.Atoi(m[1])
+		d := time.Duration(n) * time.Millisecond
+		if m[2] == "s" {
+			d = time.Duration(n) * time.Second
+		}
+		if d > maxBreak {
+			return fmt.Errorf("break time %q exceeds the %v limit", a.Value, maxBreak)
+		}
+	case "break/strength":
+		switch a.Value {
+		case "none", "x-weak", "weak", "medium", "strong", "x-strong":
+		default:
+			return fmt.Errorf("unsupported break strength %q", a.Value)
+		}
+	case "emphasis/level":
+		switch a.Value {
+		case "reduced", "none", "moderate", "strong":
+		default:
+			return fmt.Errorf("unsupported emphasis level %q", a.Value)
+		}
+	}
+	return nil
+}
+
+// Characters returns the number of characters of text in the document, excluding markup.
+func (d *SSMLDocument) Characters() int {
+	n := 0
+	walkSSML(d.Root, func(node SSMLNode) {
+		if t, ok := node.(*SSMLText); ok {
+			n += utf8.RuneCountInString(t.Text)
+		}
+	})
+	return n
+}
+
+// Voices returns the names of the voices speaking the document, in order of appearance.
+func (d *SSMLDocument) Voices() []string {
+	var voices []string
+	walkSSML(d.Root, func(node SSMLNode) {
+		if e, ok := node.(*SSMLElement); ok && e.Name == "voice" {
+			name, _ := e.Attr("name")
+			voices = append(voices, name)
+		}
+	})
+	return voices
+}
+
+// walkSSML calls fn for n and each of its descendants in document order.
+func walkSSML(n SSMLNode, fn func(SSMLNode)) {
+	fn(n)
+	if e, ok := n.(*SSMLElement); ok {
+		for _, c := range e.Children {
+			walkSSML(c, fn)
+		}
+	}
+}
+
+// VoiceParams converts each voice element of the document into a VoiceParam. An `mstts:express-as` or
+// `prosody` element wrapping the whole content of a voice is lifted into Style, Role and Prosody; other markup
+// is kept in SpeechText. Voices using attributes which VoiceParam cannot express, such as effect, fail.
+func (d *SSMLDocument) VoiceParams() ([]VoiceParam, error) {
+	locale, _ := d.Root.Attr("xml:lang")
+	var params []VoiceParam
+	for _, c := range d.Root.Children {
+		e, ok := c.(*SSMLElement)
+		if !ok || e.Name != "voice" {
+			continue
+		}
+		param, err := ssmlVoiceParam(e, Locale(locale))
+		if err != nil {
+			line, column := e.Position()
+			return nil, &SSMLError{Line: line, Column: column, Message: err.Error()}
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+func ssmlVoiceParam(voice *SSMLElement, locale Locale) (VoiceParam, error) {
+	param := VoiceParam{Locale: locale}
+	for _, a := range voice.Attrs {
+		switch a.Name {
+		case "name":
+			param.Voice = a.Value
+		case "xml:lang":
+			param.Locale = Locale(a.Value)
+		case "xml:gender":
+			gender, err := GenderString(a.Value)
+			if err != nil {
+				return param, fmt.Errorf("unsupported voice gender %q", a.Value)
+			}
+			param.Gender = gender
+		default:
+			return param, fmt.Errorf("voice attribute %s cannot be converted", a.Name)
+		}
+	}
+
+	content := voice.Children
+	for len(content) > 0 {
+		if t, ok := content[0].(*SSMLText); ok && strings.TrimSpace(t.Text) == "" {
+			content = content[1:]
+			continue
+		}
+		e, ok := content[0].(*SSMLElement)
+		if !ok || e.Name != "lexicon" {
+			break
+		}
+		uri, _ := e.Attr("uri")
+		param.LexiconURIs = append(param.LexiconURIs, uri)
+		content = content[1:]
+	}
+	if e, ok := ssmlOnlyElement(content); ok && e.Name == "mstts:express-as" {
+		for _, a := range e.Attrs {
+			switch a.Name {
+			case "style":
+				param.Style = a.Value
+			case "role":
+				param.Role = a.Value
+			case "styledegree":
+				param.StyleDegree, _ = strconv.ParseFloat(a.Value, 64)
+			}
+		}
+		content = e.Children
+	}
+	if e, ok := ssmlOnlyElement(content); ok && e.Name == "prosody" {
+		if prosody, ok := ssmlProsody(e); ok {
+			param.Prosody = prosody
+			content = e.Children
+		}
+	}
+
+	var b strings.Builder
+	for _, c := range content {
+		c.writeTo(&b)
+	}
+	param.SpeechText = strings.TrimSpace(b.String())
+	return param, nil
+}
+
+// ssmlOnlyElement returns the element of nodes when it is the only node besides whitespace.
+func ssmlOnlyElement(nodes []SSMLNode) (*SSMLElement, bool) {
+	var only *SSMLElement
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *SSMLText:
+			if strings.TrimSpace(n.Text) != "" {
+				return nil, false
+			}
+		case *SSMLElement:
+			if only != nil {
+				return nil, false
+			}
+			only = n
+		}
+	}
+	return only, only != nil
+}
+
+// ssmlProsody converts a prosody element, reporting false when it has attributes Prosody cannot express.
+func ssmlProsody(e *SSMLElement) (*Prosody, bool) {
+	p := &Prosody{}
+	for _, a := range e.Attrs {
+		switch a.Name {
+		case "rate":
+			p.Rate = ProsodyRate(a.Value)
+		case "pitch":
+			p.Pitch = ProsodyPitch(a.Value)
+		case "volume":
+			p.Volume = ProsodyVolume(a.Value)
+		case "contour":
+			contour, err := parseContour(a.Value)
+			if err != nil {
+				return nil, false
+			}
+			p.Contour = contour
+		default:
+			return nil, false
+		}
+	}
+	return p, true
+}
+
+// parseContour parses the value of the contour attribute, "(0%,+20Hz) (50%,-2st)".
+func parseContour(value string) ([]ContourPoint, error) {
+	var points []ContourPoint
+	for _, field := range strings.Fields(value) {
+		inner, ok := strings.CutPrefix(field, "(")
+		if inner, ok = strings.CutSuffix(inner, ")"); !ok {
+			return nil, fmt.Errorf("malformed contour point %q", field)
+		}
+		position, pitch, ok := strings.Cut(inner, ",")
+		if !ok || !strings.HasSuffix(position, "%") {
+			return nil, fmt.Errorf("malformed contour point %q", field)
+		}
+		f, err := strconv.ParseFloat(strings.TrimSuffix(position, "%"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed contour point %q", field)
+		}
+		points = append(points, ContourPoint{Position: f, Pitch: ProsodyPitch(pitch)})
+	}
+	return points, nil
+}
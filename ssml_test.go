@@ -0,0 +1,174 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSSML(t *testing.T) {
+	doc := `<speak version='1.0' xmlns='http://www.w3.org/2001/10/synthesis' xmlns:mstts='https://www.w3.org/2001/mstts' xml:lang='en-US'>
+  <voice name='en-US-AvaNeural'>
+    <mstts:express-as style='cheerful' styledegree='1.5'>
+      Hello <sub alias='World Wide Web'>WWW</sub> &amp; friends<break time='500ms'/>
+    </mstts:express-as>
+  </voice>
+</speak>`
+	d, err := ParseSSML(doc)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "speak", d.Root.Name)
+	lang, _ := d.Root.Attr("xml:lang")
+	assert.Equal(t, "en-US", lang)
+
+	voice := d.Root.Children[1].(*SSMLElement)
+	assert.Equal(t, "voice", voice.Name)
+	assert.Equal(t, 2, voice.Line)
+	expressAs := voice.Children[1].(*SSMLElement)
+	assert.Equal(t, "mstts:express-as", expressAs.Name)
+	assert.Equal(t, 3, expressAs.Line)
+	assert.Equal(t, 5, expressAs.Column)
+
+	// the document renders back to equivalent SSML.
+	again, err := ParseSSML(d.String())
+	assert.NoError(t, err)
+	assert.Equal(t, d.String(), again.String())
+	assert.Contains(t, d.String(), "&amp; friends")
+
+	d, err = ParseSSML("<speak version='1.0' xml:lang='en-US'><voice name='a'>it's\n&quot;here&quot;</voice></speak>")
+	if assert.NoError(t, err) {
+		assert.Contains(t, d.String(), "it's\n\"here\"", "text keeps quotes and line breaks")
+	}
+}
+
+func TestSSMLVoiceParams(t *testing.T) {
+	params := []VoiceParam{
+		{
+			SpeechText:  "Hello <sub alias='World Wide Web'>WWW</sub> &amp; friends",
+			Voice:       "en-US-AvaNeural",
+			Locale:      LocaleEnUS,
+			Gender:      GenderFemale,
+			LexiconURIs: []string{"https://example.com/lexicon.xml"},
+			Style:       "cheerful",
+			StyleDegree: 1.5,
+			Prosody:     &Prosody{Rate: RateSlow, Contour: []ContourPoint{{0, PitchRelativeHz(20)}, {50, PitchSemitones(-2)}}},
+		},
+		{SpeechText: "Bye.", Voice: "en-US-GuyNeural", Locale: LocaleEnGB, Gender: GenderMale},
+	}
+	xml, err := voicesXMLRender(LocaleEnUS, params)
+	assert.NoError(t, err)
+	d, err := ParseSSML(xml)
+	if !assert.NoError(t, err) {
+		return
+	}
+	got, err := d.VoiceParams()
+	assert.NoError(t, err)
+	assert.Equal(t, params, got)
+
+	// a prosody element which does not wrap the whole content stays in the text.
+	d, err = ParseSSML("<speak version='1.0' xml:lang='en-US'><voice name='a'>\n<prosody range='high'>Hi</prosody> there</voice></speak>")
+	if assert.NoError(t, err) {
+		got, err = d.VoiceParams()
+		assert.NoError(t, err)
+		assert.Equal(t, []VoiceParam{{SpeechText: "<prosody range='high'>Hi</prosody> there", Voice: "a", Locale: LocaleEnUS}}, got)
+	}
+
+	d, err = ParseSSML("<speak version='1.0' xml:lang='en-US'><voice name='a' effect='eq_car'>Hi</voice></speak>")
+	if assert.NoError(t, err) {
+		_, err = d.VoiceParams()
+		assert.ErrorContains(t, err, "effect")
+	}
+}
+
+func TestParseSSMLRendered(t *testing.T) {
+	xml, err := voiceXMLRender(VoiceParam{
+		SpeechText:  "Hello",
+		Voice:       "en-US-AvaNeural",
+		Locale:      LocaleEnUS,
+		Gender:      GenderFemale,
+		LexiconURIs: []string{"https://example.com/lexicon.xml"},
+		Style:       "cheerful",
+		Prosody:     &Prosody{Rate: RateSlow, Pitch: PitchSemitones(-1)},
+	})
+	assert.NoError(t, err)
+	_, err = ParseSSML(xml)
+	assert.NoError(t, err, "rendered payloads should be valid SSML")
+}
+
+func TestParseSSMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		line int
+		err  string
+	}{
+		{"syntax", "<speak version='1.0' xml:lang='en-US'>\n<voice name='a'>hi</speak>", 2, "element <voice> closed by </speak>"},
+		{"root", "<voice name='a'>hi</voice>", 1, "root element must be speak"},
+		{"missing attribute", "<speak version='1.0'><voice name='a'>hi</voice></speak>", 1, "speak requires the xml:lang attribute"},
+		{"attribute case", "<speak version='1.0' xml:lang='en-US'><voice Name='a'>hi</voice></speak>", 1, "voice does not accept the Name attribute"},
+		{"unknown attribute", "<speak version='1.0' xml:lang='en-US'>\n<voice name='a' speed='2'>hi</voice></speak>", 2, "voice does not accept the speed attribute"},
+		{"nesting", "<speak version='1.0' xml:lang='en-US'><voice name='a'><sub alias='x'><break/></sub></voice></speak>", 1, "break is not allowed inside sub"},
+		{"text in speak", "<speak version='1.0' xml:lang='en-US'>hi<voice name='a'>hi</voice></speak>", 1, "speak cannot contain text"},
+		{"unknown element", "<speak version='1.0' xml:lang='en-US'><voice name='a'><blink>hi</blink></voice></speak>", 1, "unsupported element blink"},
+		{"no voice", "<speak version='1.0' xml:lang='en-US'></speak>", 1, "no voice element"},
+		{"mstts namespace", "<speak version='1.0' xml:lang='en-US'><voice name='a'><mstts:express-as style='sad'>hi</mstts:express-as></voice></speak>", 1, "xmlns:mstts"},
+		{"prosody value", "<speak version='1.0' xml:lang='en-US'><voice name='a'><prosody rate='5'>hi</prosody></voice></speak>", 1, "prosody rate"},
+		{"break time", "<speak version='1.0' xml:lang='en-US'><voice name='a'><break time='30s'/></voice></speak>", 1, "break time"},
+		{"voice limit", "<speak version='1.0' xml:lang='en-US'>" + strings.Repeat("<voice name='a'>hi</voice>", maxVoiceElements+1) + "</speak>", 1, "voice elements"},
+		{"size limit", "<speak version='1.0' xml:lang='en-US'><voice name='a'>" + strings.Repeat("a", maxSSMLSize) + "</voice></speak>", 1, "byte limit"},
+	}
+	for _, tt := range tests {
+		_, err := ParseSSML(tt.doc)
+		if !assert.Error(t, err, tt.name) {
+			continue
+		}
+		assert.Contains(t, err.Error(), tt.err, tt.name)
+		var line int
+		switch e := err.(type) {
+		case *SSMLError:
+			line = e.Line
+		case SSMLErrors:
+			line = e[0].Line
+		}
+		assert.Equal(t, tt.line, line, tt.name)
+	}
+}
+
+func TestParseSSMLMaxBillableCharacters(t *testing.T) {
+	doc := "<speak version='1.0' xml:lang='en-US'><voice name='a'>hello world</voice></speak>"
+	_, err := ParseSSML(doc, MaxBillableCharacters(11))
+	assert.NoError(t, err)
+
+	_, err = ParseSSML(doc, MaxBillableCharacters(5))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "11 billable characters, exceeding the limit of 5")
+	}
+}
+
+func TestSynthesizeSSML(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("SYS4096"))
+	}))
+	defer ts.Close()
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL}
+
+	_, err := az.SynthesizeSSMLWithContext(context.Background(), "<speak><voice>hi</voice></speak>", AudioOutput_riff_8khz_8bit_mono_alaw)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	assert.Equal(t, 0, requests, "invalid documents should not be sent")
+
+	payload, err := az.SynthesizeSSMLWithContext(context.Background(), "<speak version='1.0' xml:lang='en-US'><voice name='a'>hi</voice></speak>", AudioOutput_riff_8khz_8bit_mono_alaw)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("SYS4096"), payload)
+
+	WithMaxSSMLCharacters(1)(az)
+	_, err = az.SynthesizeSSMLWithContext(context.Background(), "<speak version='1.0' xml:lang='en-US'><voice name='a'>hi</voice></speak>", AudioOutput_riff_8khz_8bit_mono_alaw)
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests, "documents over the limit should not be sent")
+}
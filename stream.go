@@ -0,0 +1,261 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultStreamLookahead is the number of sentences synthesized ahead of the one being emitted, see
+// WithStreamLookahead.
+const defaultStreamLookahead = 3
+
+// maxStreamSentence is the length in bytes after which text without a sentence boundary is split at a space,
+// so that a run-on reply does not delay the speech.
+const maxStreamSentence = 1000
+
+// abbreviations end with a period which does not end a sentence.
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true, "jr": true, "st": true,
+	"vs": true, "etc": true, "e.g": true, "i.e": true, "no": true, "approx": true,
+}
+
+// SentenceAudio is the audio of one sentence of a stream, see SynthesizeStream. A stream ends after the first
+// SentenceAudio with an error.
+type SentenceAudio struct {
+	Index int // position of the sentence in the stream, starting at zero
+	Text  string
+	Audio []byte
+	Err   error
+}
+
+// SynthesizeStream speaks text arriving in fragments, such as the tokens of a language model reply, as it
+// arrives. The text is split into sentences, each synthesized as soon as it is complete with up to
+// WithStreamLookahead sentences in flight, and the audio is sent in order on the returned channel. The stream
+// ends when fragments is closed; the channel is closed after the last sentence.
+//
+// Fragments are plain text, they are escaped for SSML. param configures the voice, its SpeechText is ignored.
+// The audio of each sentence is a complete file, so choose a raw or compressed format, such as
+// AudioOutput_raw_24khz_16bit_mono_pcm or AudioOutput_audio_24khz_48kbitrate_mono_mp3, to play sentences back
+// to back. The caller must receive until the channel is closed or cancel ctx.
+func (az *AzureCSTextToSpeech) SynthesizeStream(ctx context.Context, fragments <-chan string, param VoiceParam, audioOutput AudioOutput) <-chan SentenceAudio {
+	return az.stream(ctx, func(ctx context.Context) (string, error) {
+		select {
+		case fragment, ok := <-fragments:
+			if !ok {
+				return "", io.EOF
+			}
+			return fragment, nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}, param, audioOutput)
+}
+
+// SynthesizeReader is SynthesizeStream for text read from r. r is read in a goroutine, so canceling ctx ends
+// the stream even while a Read is blocked; that Read keeps its goroutine until it returns, close r to
+// release it.
+func (az *AzureCSTextToSpeech) SynthesizeReader(ctx context.Context, r io.Reader, param VoiceParam, audioOutput AudioOutput) <-chan SentenceAudio {
+	type chunk struct {
+		text string
+		err  error
+	}
+	chunks := make(chan chunk)
+	var once sync.Once
+	return az.stream(ctx, func(ctx context.Context) (string, error) {
+		once.Do(func() {
+			go func() {
+				buf := make([]byte, 4096)
+				for {
+					n, err := r.Read(buf)
+					select {
+					case chunks <- chunk{text: string(buf[:n]), err: err}:
+					case <-ctx.Done():
+						return
+					}
+					if err != nil {
+						return
+					}
+				}
+			}()
+		})
+		select {
+		case c := <-chunks:
+			return c.text, c.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}, param, audioOutput)
+}
+
+// stream synthesizes the sentences of the text returned by next, which returns io.EOF at the end of the text.
+func (az *AzureCSTextToSpeech) stream(ctx context.Context, next func(context.Context) (string, error), param VoiceParam, audioOutput AudioOutput) <-chan SentenceAudio {
+	ctx, cancel := context.WithCancel(ctx)
+	lookahead := az.streamLookahead
+	if lookahead <= 0 {
+		lookahead = defaultStreamLookahead
+	}
+	out := make(chan SentenceAudio)
+	// a sentence holds a slot of sem from being started until it is received from out, so at most lookahead
+	// sentences are ever synthesized ahead of the receiver.
+	sem := make(chan struct{}, lookahead)
+	pending := make(chan chan SentenceAudio, lookahead)
+
+	go func() {
+		defer close(pending)
+		index := 0
+		start := func(text string) bool {
+			if strings.TrimSpace(text) == "" {
+				return true
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return false
+			}
+			result := make(chan SentenceAudio, 1)
+			pending <- result
+			p := param
+			p.SpeechText = xmlTextEscaper.Replace(strings.TrimSpace(text))
+			go func(index int) {
+				audio, err := az.SynthesizeWithContext(ctx, p, audioOutput)
+				result <- SentenceAudio{Index: index, Text: text, Audio: audio, Err: err}
+			}(index)
+			index++
+			return true
+		}
+
+		var splitter sentenceSplitter
+		for {
+			fragment, err := next(ctx)
+			for _, sentence := range splitter.write(fragment) {
+				if !start(sentence) {
+					return
+				}
+			}
+			if err == io.EOF {
+				start(splitter.flush())
+				return
+			}
+			if err != nil {
+				result := make(chan SentenceAudio, 1)
+				result <- SentenceAudio{Index: index, Err: err}
+				select {
+				case sem <- struct{}{}:
+					pending <- result
+				case <-ctx.Done():
+				}
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(out)
+		defer cancel()
+		for result := range pending {
+			var sentence SentenceAudio
+			select {
+			case sentence = <-result:
+			case <-ctx.Done():
+				sentence = SentenceAudio{Err: ctx.Err()}
+			}
+			select {
+			case out <- sentence:
+			case <-ctx.Done():
+				return
+			}
+			<-sem
+			if sentence.Err != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// sentenceSplitter splits text arriving in fragments into sentences.
+type sentenceSplitter struct {
+	buf string
+}
+
+// write appends text and returns the sentences completed by it.
+func (s *sentenceSplitter) write(text string) []string {
+	s.buf += text
+	var sentences []string
+	for {
+		end := sentenceEnd(s.buf)
+		if end < 0 && len(s.buf) > maxStreamSentence {
+			end = strings.LastIndexFunc(s.buf[:maxStreamSentence], unicode.IsSpace) + 1
+			if end <= 0 {
+				end = maxStreamSentence
+				for !utf8.RuneStart(s.buf[end]) {
+					end--
+				}
+			}
+		}
+		if end < 0 {
+			return sentences
+		}
+		sentences = append(sentences, s.buf[:end])
+		s.buf = s.buf[end:]
+	}
+}
+
+// flush returns the remaining text.
+func (s *sentenceSplitter) flush() string {
+	text := s.buf
+	s.buf = ""
+	return text
+}
+
+// sentenceEnd returns the offset after the first complete sentence of text, or -1. A sentence ends with a line
+// break, with a full-width terminator such as "。", or with ".", "!", "?" or "…" and closing quotes or brackets
+// followed by a space. A period ending text is not a boundary yet, as the next fragment may continue a number.
+func sentenceEnd(text string) int {
+	for i, r := range text {
+		switch r {
+		case '\n':
+			if strings.TrimSpace(text[:i]) != "" {
+				return i + 1
+			}
+		case '。', '！', '？':
+			return i + utf8.RuneLen(r)
+		case '.', '!', '?', '…':
+			end := i + utf8.RuneLen(r)
+			for end < len(text) {
+				closing, size := utf8.DecodeRuneInString(text[end:])
+				if !strings.ContainsRune(`"')]”’»」`, closing) {
+					break
+				}
+				end += size
+			}
+			if end >= len(text) {
+				return -1
+			}
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(next) {
+				continue
+			}
+			if r == '.' && isAbbreviation(text[:i]) {
+				continue
+			}
+			return end
+		}
+	}
+	return -1
+}
+
+// isAbbreviation reports whether the word ending text is an abbreviation or an initial, which end with a
+// period that does not end the sentence.
+func isAbbreviation(text string) bool {
+	word := text[strings.LastIndexFunc(text, unicode.IsSpace)+1:]
+	if utf8.RuneCountInString(word) == 1 {
+		r, _ := utf8.DecodeRuneInString(word)
+		return unicode.IsUpper(r)
+	}
+	return abbreviations[strings.ToLower(word)]
+}
@@ -0,0 +1,142 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentenceSplitter(t *testing.T) {
+	tests := []struct {
+		fragments []string
+		sentences []string
+	}{
+		{[]string{"Hello", " world. How", " are you? I'm", " fine"}, []string{"Hello world.", " How are you?", " I'm fine"}},
+		{[]string{"Pi is 3.", "14. Done"}, []string{"Pi is 3.14.", " Done"}},
+		{[]string{"Ask Dr. Smith and J. Doe, e.g. today. Ok"}, []string{"Ask Dr. Smith and J. Doe, e.g. today.", " Ok"}},
+		{[]string{`He said "stop!" then left.`, " "}, []string{`He said "stop!"`, " then left."}},
+		{[]string{"你好。今天天气很好！", "是吗"}, []string{"你好。", "今天天气很好！", "是吗"}},
+		{[]string{"- first item\n- second", " item"}, []string{"- first item\n", "- second item"}},
+		{[]string{"Wait...", " what?"}, []string{"Wait...", " what?"}},
+	}
+	for _, tt := range tests {
+		var s sentenceSplitter
+		var sentences []string
+		for _, f := range tt.fragments {
+			sentences = append(sentences, s.write(f)...)
+		}
+		if rest := s.flush(); strings.TrimSpace(rest) != "" {
+			sentences = append(sentences, rest)
+		}
+		assert.Equal(t, tt.sentences, sentences, "%q", tt.fragments)
+	}
+
+	var s sentenceSplitter
+	sentences := s.write(strings.Repeat("word ", 300))
+	if assert.Len(t, sentences, 1) {
+		assert.LessOrEqual(t, len(sentences[0]), maxStreamSentence)
+		assert.True(t, strings.HasSuffix(sentences[0], " "))
+	}
+}
+
+var voiceTextPattern = regexp.MustCompile(`name='[^']*'>(.*)</voice>`)
+
+// newEchoServer returns a server answering with the text of the synthesized voice, failing texts containing
+// "fail". Earlier requests take longer, so that their responses arrive out of order.
+func newEchoServer(t *testing.T) (*AzureCSTextToSpeech, *atomic.Int32) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		text := voiceTextPattern.FindStringSubmatch(string(body))[1]
+		if strings.Contains(text, "fail") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		time.Sleep(time.Duration(5-min(n, 5)) * 10 * time.Millisecond)
+		w.Write([]byte(text))
+	}))
+	t.Cleanup(ts.Close)
+	return &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL}, &requests
+}
+
+func TestSynthesizeStream(t *testing.T) {
+	az, _ := newEchoServer(t)
+	fragments := make(chan string)
+	go func() {
+		defer close(fragments)
+		for _, f := range []string{"One. Two", ". Three & four.", " Five"} {
+			fragments <- f
+		}
+	}()
+
+	var audio []string
+	for sentence := range az.SynthesizeStream(context.Background(), fragments, VoiceParam{Voice: "ar-EG-Hoda", Locale: LocaleEnUS}, AudioOutput_raw_16khz_16bit_mono_pcm) {
+		assert.NoError(t, sentence.Err)
+		assert.Equal(t, len(audio), sentence.Index)
+		audio = append(audio, string(sentence.Audio))
+	}
+	assert.Equal(t, []string{"One.", "Two.", "Three &amp; four.", "Five"}, audio)
+}
+
+func TestSynthesizeStreamLookahead(t *testing.T) {
+	az, requests := newEchoServer(t)
+	WithStreamLookahead(1)(az)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sentences := az.SynthesizeReader(ctx, strings.NewReader("One. Two. Three."), VoiceParam{Voice: "ar-EG-Hoda", Locale: LocaleEnUS}, AudioOutput_raw_16khz_16bit_mono_pcm)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(1), requests.Load(), "a sentence waiting for the receiver holds its slot")
+	<-sentences
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestSynthesizeReaderError(t *testing.T) {
+	az, requests := newEchoServer(t)
+	WithStreamLookahead(1)(az)
+	text := "One. This will fail. Three. Four. Five. Six."
+
+	var sentences []SentenceAudio
+	for sentence := range az.SynthesizeReader(context.Background(), strings.NewReader(text), VoiceParam{Voice: "ar-EG-Hoda", Locale: LocaleEnUS}, AudioOutput_raw_16khz_16bit_mono_pcm) {
+		sentences = append(sentences, sentence)
+	}
+	if assert.Len(t, sentences, 2) {
+		assert.NoError(t, sentences[0].Err)
+		assert.Error(t, sentences[1].Err)
+	}
+	assert.LessOrEqual(t, requests.Load(), int32(3), "the stream stops after the failure")
+}
+
+func TestSynthesizeReaderCancel(t *testing.T) {
+	az, _ := newEchoServer(t)
+	r, w := io.Pipe()
+	defer w.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	sentences := az.SynthesizeReader(ctx, r, VoiceParam{Voice: "ar-EG-Hoda", Locale: LocaleEnUS}, AudioOutput_raw_16khz_16bit_mono_pcm)
+	go w.Write([]byte("One. Two"))
+	sentence := <-sentences
+	assert.Equal(t, "One.", strings.TrimSpace(sentence.Text))
+
+	// the reader blocks until it is closed, canceling ctx still ends the stream.
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		for range sentences {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the stream ignores the cancellation of ctx")
+	}
+}
@@ -0,0 +1,69 @@
+package subtitle
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteSRT writes cues in the SubRip format.
+func WriteSRT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	for _, c := range cues {
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", c.Index, timestamp(c.Start, ','), timestamp(c.End, ','), c.Text())
+	}
+	return bw.Flush()
+}
+
+// webVTTEscaper escapes the characters which would be taken as markup of a WebVTT cue.
+var webVTTEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// WriteWebVTT writes cues in the WebVTT format.
+func WriteWebVTT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n\n")
+	for _, c := range cues {
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", c.Index, timestamp(c.Start, '.'), timestamp(c.End, '.'), webVTTEscaper.Replace(c.Text()))
+	}
+	return bw.Flush()
+}
+
+// timestamp formats d as hours, minutes, seconds and milliseconds, separated from the seconds by sep.
+func timestamp(d time.Duration, sep byte) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// jsonCue and jsonWord are the timing export of WriteJSON, in milliseconds.
+type jsonCue struct {
+	Index   int        `json:"index"`
+	StartMs int64      `json:"start_ms"`
+	EndMs   int64      `json:"end_ms"`
+	Text    string     `json:"text"`
+	Words   []jsonWord `json:"words"`
+}
+
+type jsonWord struct {
+	Text    string       `json:"text"`
+	Kind    BoundaryKind `json:"kind"`
+	StartMs int64        `json:"start_ms"`
+	EndMs   int64        `json:"end_ms"`
+}
+
+// WriteJSON writes the timing of cues and their words as a JSON array, for players highlighting the spoken
+// word:
+//
+//	[{"index": 1, "start_ms": 0, "end_ms": 850, "text": "Hello world.", "words": [{"text": "Hello", "kind": "word", "start_ms": 0, "end_ms": 400}, ...]}]
+func WriteJSON(w io.Writer, cues []Cue) error {
+	out := make([]jsonCue, len(cues))
+	for i, c := range cues {
+		out[i] = jsonCue{Index: c.Index, StartMs: c.Start.Milliseconds(), EndMs: c.End.Milliseconds(), Text: c.Text(), Words: []jsonWord{}}
+		for _, b := range c.Words {
+			out[i].Words = append(out[i].Words, jsonWord{Text: b.Text, Kind: b.Kind, StartMs: b.Offset.Milliseconds(), EndMs: b.End().Milliseconds()})
+		}
+	}
+	return json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,51 @@
+package subtitle
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormats(t *testing.T) {
+	cues := []Cue{
+		{Index: 1, Start: 0, End: 1500 * time.Millisecond, Lines: []string{"Fish & Chips", "<cheap>"}},
+		{Index: 2, Start: 3723004 * time.Millisecond, End: 3724000 * time.Millisecond, Lines: []string{"Bye."}, Words: []Boundary{
+			{Kind: BoundaryWord, Text: "Bye", Offset: 3723004 * time.Millisecond, Duration: 500 * time.Millisecond},
+			{Kind: BoundaryPunctuation, Text: ".", Offset: 3723504 * time.Millisecond},
+		}},
+	}
+
+	var srt bytes.Buffer
+	assert.NoError(t, WriteSRT(&srt, cues))
+	assert.Equal(t, "1\n00:00:00,000 --> 00:00:01,500\nFish & Chips\n<cheap>\n\n"+
+		"2\n01:02:03,004 --> 01:02:04,000\nBye.\n\n", srt.String())
+
+	var vtt bytes.Buffer
+	assert.NoError(t, WriteWebVTT(&vtt, cues))
+	assert.Equal(t, "WEBVTT\n\n"+
+		"1\n00:00:00.000 --> 00:00:01.500\nFish &amp; Chips\n&lt;cheap&gt;\n\n"+
+		"2\n01:02:03.004 --> 01:02:04.000\nBye.\n\n", vtt.String())
+
+	var timing bytes.Buffer
+	assert.NoError(t, WriteJSON(&timing, cues))
+	assert.JSONEq(t, `[
+		{"index": 1, "start_ms": 0, "end_ms": 1500, "text": "Fish & Chips\n<cheap>", "words": []},
+		{"index": 2, "start_ms": 3723004, "end_ms": 3724000, "text": "Bye.", "words": [
+			{"text": "Bye", "kind": "word", "start_ms": 3723004, "end_ms": 3723504},
+			{"text": ".", "kind": "punctuation", "start_ms": 3723504, "end_ms": 3723504}
+		]}
+	]`, timing.String())
+}
+
+func TestEstimatedCaptions(t *testing.T) {
+	// captions of a clip synthesized without boundary events.
+	cues := Cues(Estimate("Welcome to the show. Today we talk about captions.", 4*time.Second), Config{})
+	if assert.Len(t, cues, 2) {
+		assert.Equal(t, "Welcome to the show.", cues[0].Text())
+		assert.Equal(t, "Today we talk about captions.", cues[1].Text())
+		assert.Less(t, cues[0].End, cues[1].Start)
+		assert.Equal(t, 4*time.Second, cues[1].End)
+	}
+}
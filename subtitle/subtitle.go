@@ -0,0 +1,194 @@
+// Package subtitle generates captions, as SRT or WebVTT, and timing exports, as JSON, from the word and sentence
+// boundaries of synthesized speech.
+//
+// The REST API of the Speech service does not report boundaries. Pass the WordBoundary events of the Speech
+// SDK, or estimate boundaries from the text and the duration of the audio with Estimate.
+package subtitle
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// BoundaryKind is the kind of a Boundary, named like the boundary types of the Speech SDK.
+type BoundaryKind string
+
+const (
+	BoundaryWord        BoundaryKind = "word"
+	BoundaryPunctuation BoundaryKind = "punctuation"
+	// BoundarySentence marks the start of a sentence, whose words follow as separate boundaries.
+	BoundarySentence BoundaryKind = "sentence"
+)
+
+// Boundary is the text spoken from Offset for Duration into the audio.
+type Boundary struct {
+	Kind     BoundaryKind
+	Text     string
+	Offset   time.Duration
+	Duration time.Duration
+}
+
+// End returns the offset at which b has been spoken.
+func (b Boundary) End() time.Duration {
+	return b.Offset + b.Duration
+}
+
+// Defaults of a Config.
+const (
+	defaultMaxLineLength  = 42
+	defaultMaxLines       = 2
+	defaultMaxCueDuration = 7 * time.Second
+)
+
+// Config lays out the cues of Cues. Zero fields take their defaults.
+type Config struct {
+	MaxLineLength  int           // in characters, default 42; longer words get a line of their own
+	MaxLines       int           // per cue, default 2
+	MaxCueDuration time.Duration // default 7 seconds
+}
+
+// Cue is a caption shown from Start to End.
+type Cue struct {
+	Index      int // starting at 1
+	Start, End time.Duration
+	Lines      []string
+	Words      []Boundary // the word and punctuation boundaries of the cue
+}
+
+// Text returns the lines of c joined by line breaks.
+func (c Cue) Text() string {
+	return strings.Join(c.Lines, "\n")
+}
+
+// sentenceTerminators end a sentence when they end a word or punctuation boundary.
+const sentenceTerminators = ".!?…。！？"
+
+// Cues lays out boundaries, ordered by offset, as captions. A cue holds as many words as fit its lines and
+// duration, and a new cue starts with every sentence, marked by a sentence boundary or by the terminator of
+// the previous sentence, so that captions do not run across sentences.
+func Cues(boundaries []Boundary, cfg Config) []Cue {
+	cfg.MaxLineLength = orDefault(cfg.MaxLineLength, defaultMaxLineLength)
+	cfg.MaxLines = orDefault(cfg.MaxLines, defaultMaxLines)
+	cfg.MaxCueDuration = orDefault(cfg.MaxCueDuration, defaultMaxCueDuration)
+
+	var (
+		cues     []Cue
+		words    []Boundary
+		sentence bool // the next word starts a sentence
+	)
+	flush := func() {
+		if len(words) == 0 {
+			return
+		}
+		cues = append(cues, Cue{
+			Index: len(cues) + 1,
+			Start: words[0].Offset,
+			End:   words[len(words)-1].End(),
+			Lines: wrap(words, cfg.MaxLineLength),
+			Words: words,
+		})
+		words = nil
+	}
+	for _, b := range boundaries {
+		switch b.Kind {
+		case BoundarySentence:
+			sentence = true
+			continue
+		case BoundaryWord:
+			if sentence {
+				flush()
+			} else if len(words) > 0 {
+				candidate := append(words[:len(words):len(words)], b)
+				if b.End()-words[0].Offset > cfg.MaxCueDuration || len(wrap(candidate, cfg.MaxLineLength)) > cfg.MaxLines {
+					flush()
+				}
+			}
+			sentence = false
+		}
+		// punctuation stays with the preceding word, even when it overflows the cue.
+		words = append(words, b)
+		if strings.ContainsAny(lastRune(b.Text), sentenceTerminators) {
+			sentence = true
+		}
+	}
+	flush()
+	return cues
+}
+
+// wrap joins words into lines of at most width characters.
+func wrap(words []Boundary, width int) []string {
+	var lines []string
+	var line strings.Builder
+	for i, w := range words {
+		space := i > 0 && needsSpace(words[i-1], w)
+		if line.Len() > 0 && space && w.Kind == BoundaryWord &&
+			utf8.RuneCountInString(line.String())+1+utf8.RuneCountInString(w.Text) > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			space = false
+		}
+		if space && line.Len() > 0 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(w.Text)
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// needsSpace reports whether a space separates b from the preceding boundary prev.
+func needsSpace(prev, b Boundary) bool {
+	if strings.ContainsAny(lastRune(prev.Text), "([{\"“‘¿¡") {
+		return false
+	}
+	if b.Kind == BoundaryPunctuation {
+		r, _ := utf8.DecodeRuneInString(b.Text)
+		return strings.ContainsRune("([{“‘¿¡", r)
+	}
+	return true
+}
+
+func lastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[len(s)-size:]
+}
+
+func orDefault[T int | time.Duration](v, def T) T {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+// Estimate returns word and sentence boundaries for text spoken in duration, sharing the duration among the
+// words by their length. It approximates the timing of voices speaking at an even rate, for audio synthesized
+// without boundary events; the duration of a clip can be found with audio.Probe.
+func Estimate(text string, duration time.Duration) []Boundary {
+	words := strings.Fields(text)
+	total := len(words) - 1 // a character worth of pause between words
+	for _, w := range words {
+		total += utf8.RuneCountInString(w)
+	}
+	var (
+		boundaries []Boundary
+		spoken     int
+		sentence   = true
+	)
+	at := func(characters int) time.Duration {
+		return time.Duration(int64(duration) * int64(characters) / int64(total))
+	}
+	for _, w := range words {
+		start := at(spoken)
+		if sentence {
+			boundaries = append(boundaries, Boundary{Kind: BoundarySentence, Offset: start})
+		}
+		n := utf8.RuneCountInString(w)
+		boundaries = append(boundaries, Boundary{Kind: BoundaryWord, Text: w, Offset: start, Duration: at(spoken+n) - start})
+		spoken += n + 1
+		sentence = strings.ContainsAny(lastRune(w), sentenceTerminators)
+	}
+	return boundaries
+}
@@ -0,0 +1,76 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// words returns word boundaries of 300 ms each, with punctuation attached to the words as separate boundaries.
+func words(text string) []Boundary {
+	var boundaries []Boundary
+	offset := time.Duration(0)
+	for _, w := range strings.Fields(text) {
+		punctuation := ""
+		if strings.ContainsAny(w[len(w)-1:], ".,!?") {
+			w, punctuation = w[:len(w)-1], w[len(w)-1:]
+		}
+		boundaries = append(boundaries, Boundary{Kind: BoundaryWord, Text: w, Offset: offset, Duration: 250 * time.Millisecond})
+		offset += 300 * time.Millisecond
+		if punctuation != "" {
+			boundaries = append(boundaries, Boundary{Kind: BoundaryPunctuation, Text: punctuation, Offset: offset - 50*time.Millisecond})
+		}
+	}
+	return boundaries
+}
+
+func TestCues(t *testing.T) {
+	cues := Cues(words("Hello, world. This is a longer sentence which does not fit on a single line of twenty characters."), Config{MaxLineLength: 20})
+	var texts []string
+	for _, c := range cues {
+		texts = append(texts, c.Text())
+	}
+	assert.Equal(t, []string{
+		"Hello, world.",
+		"This is a longer\nsentence which does",
+	}, texts[:2])
+	assert.Len(t, cues, 4)
+	assert.Equal(t, "not fit on a single\nline of twenty", cues[2].Text())
+	assert.Equal(t, "characters.", cues[3].Text())
+
+	assert.Equal(t, 1, cues[0].Index)
+	assert.Equal(t, time.Duration(0), cues[0].Start)
+	assert.Equal(t, 550*time.Millisecond, cues[0].End)
+	assert.Equal(t, 600*time.Millisecond, cues[1].Start)
+	assert.Len(t, cues[0].Words, 4)
+
+	// the duration of a cue is limited.
+	cues = Cues(words("one two three four five six"), Config{MaxCueDuration: time.Second})
+	if assert.Len(t, cues, 2) {
+		assert.Equal(t, "one two three", cues[0].Text())
+		assert.Equal(t, "four five six", cues[1].Text())
+	}
+
+	// sentence boundaries start new cues without terminators.
+	boundaries := words("first part second part")
+	boundaries = append(boundaries[:2:2], append([]Boundary{{Kind: BoundarySentence, Offset: 600 * time.Millisecond}}, boundaries[2:]...)...)
+	cues = Cues(boundaries, Config{})
+	if assert.Len(t, cues, 2) {
+		assert.Equal(t, "first part", cues[0].Text())
+		assert.Equal(t, "second part", cues[1].Text())
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	boundaries := Estimate("Hi there. Bye!", 1400*time.Millisecond)
+	assert.Equal(t, []Boundary{
+		{Kind: BoundarySentence},
+		{Kind: BoundaryWord, Text: "Hi", Duration: 200 * time.Millisecond},
+		{Kind: BoundaryWord, Text: "there.", Offset: 300 * time.Millisecond, Duration: 600 * time.Millisecond},
+		{Kind: BoundarySentence, Offset: 1000 * time.Millisecond},
+		{Kind: BoundaryWord, Text: "Bye!", Offset: 1000 * time.Millisecond, Duration: 400 * time.Millisecond},
+	}, boundaries)
+	assert.Empty(t, Estimate(" ", time.Second))
+}
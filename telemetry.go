@@ -0,0 +1,149 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName identifies the spans and metrics recorded by this package.
+const instrumentationName = "github.com/WqyJh/azuretexttospeech"
+
+// Operations recorded as spans and as the "azuretts.operation" metric attribute.
+const (
+	operationSynthesize   = "synthesize"
+	operationTokenRefresh = "token_refresh"
+	operationListVoices   = "list_voices"
+)
+
+// Attribute keys of the spans and metrics.
+const (
+	attrOperation  = attribute.Key("azuretts.operation")
+	attrVoice      = attribute.Key("azuretts.voice")
+	attrFormat     = attribute.Key("azuretts.format")
+	attrAudioBytes = attribute.Key("azuretts.audio.bytes")
+	attrAttempt    = attribute.Key("azuretts.attempt")
+	attrStatusCode = attribute.Key("http.response.status_code")
+	attrErrorType  = attribute.Key("error.type")
+)
+
+// latencyBuckets are the histogram boundaries, in seconds, of the latency metrics.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 10, 30}
+
+// telemetry holds the OpenTelemetry instruments of a client, see WithTracerProvider and WithMeterProvider.
+type telemetry struct {
+	tracerProvider       trace.TracerProvider
+	meterProvider        metric.MeterProvider
+	tracer               trace.Tracer
+	requestDuration      metric.Float64Histogram
+	timeToFirstByte      metric.Float64Histogram
+	requestErrors        metric.Int64Counter
+	tokenRefreshFailures metric.Int64Counter
+}
+
+// noopTelemetry is used by clients without a tracer or meter provider.
+var noopTelemetry = newTelemetry(tracenoop.NewTracerProvider(), metricnoop.NewMeterProvider())
+
+func newTelemetry(tp trace.TracerProvider, mp metric.MeterProvider) *telemetry {
+	if tp == nil {
+		tp = tracenoop.NewTracerProvider()
+	}
+	if mp == nil {
+		mp = metricnoop.NewMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+	t := &telemetry{tracerProvider: tp, meterProvider: mp, tracer: tp.Tracer(instrumentationName)}
+	// instrument creation only fails for invalid names, which are constant here. The returned
+	// instruments are usable no-ops in that case.
+	t.requestDuration, _ = meter.Float64Histogram("azuretts.request.duration",
+		metric.WithDescription("Duration of requests to the Azure Speech service."), metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(latencyBuckets...))
+	t.timeToFirstByte, _ = meter.Float64Histogram("azuretts.synthesis.time_to_first_byte",
+		metric.WithDescription("Time from sending a synthesis request to receiving the first byte of the response."), metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(latencyBuckets...))
+	t.requestErrors, _ = meter.Int64Counter("azuretts.request.errors",
+		metric.WithDescription("Failed requests to the Azure Speech service by HTTP status code."), metric.WithUnit("{request}"))
+	t.tokenRefreshFailures, _ = meter.Int64Counter("azuretts.token_refresh.failures",
+		metric.WithDescription("Failed attempts to refresh the access token."), metric.WithUnit("{refresh}"))
+	return t
+}
+
+type attemptsKey struct{}
+
+// withAttempts returns a copy of ctx that numbers the requests sent for it by failover and hedging, unless ctx
+// already does so.
+func withAttempts(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(attemptsKey{}).(*atomic.Int32); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, attemptsKey{}, new(atomic.Int32))
+}
+
+// nextAttempt returns the number, starting at 1, of the request about to be sent for ctx.
+func nextAttempt(ctx context.Context) int {
+	if n, ok := ctx.Value(attemptsKey{}).(*atomic.Int32); ok {
+		return int(n.Add(1))
+	}
+	return 1
+}
+
+// telemetry returns the instruments of the client.
+func (az *AzureCSTextToSpeech) telemetry() *telemetry {
+	if az.tel == nil {
+		return noopTelemetry
+	}
+	return az.tel
+}
+
+// operationSpan tracks a single request to the Speech service.
+type operationSpan struct {
+	tel       *telemetry
+	span      trace.Span
+	operation string
+	start     time.Time
+	status    int // HTTP status code of the response, zero when no response was received
+}
+
+// startOperation starts a span for operation, which is ended by calling end on the result.
+func (t *telemetry) startOperation(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, *operationSpan) {
+	ctx, span := t.tracer.Start(ctx, "azuretts."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attrOperation.String(operation))...))
+	return ctx, &operationSpan{tel: t, span: span, operation: operation, start: time.Now()}
+}
+
+// firstByte records the time to first byte of the response.
+func (o *operationSpan) firstByte(ctx context.Context) {
+	o.tel.timeToFirstByte.Record(ctx, time.Since(o.start).Seconds(), metric.WithAttributes(attrOperation.String(o.operation)))
+}
+
+// end ends the span, recording the request duration and the error, if any.
+func (o *operationSpan) end(ctx context.Context, err error) {
+	attrs := []attribute.KeyValue{attrOperation.String(o.operation)}
+	if o.status != 0 {
+		attrs = append(attrs, attrStatusCode.Int(o.status))
+		o.span.SetAttributes(attrStatusCode.Int(o.status))
+	}
+	if err != nil {
+		errorType := "network"
+		if o.status != 0 {
+			errorType = strconv.Itoa(o.status)
+		}
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+		o.tel.requestErrors.Add(ctx, 1, metric.WithAttributes(append(attrs, attrErrorType.String(errorType))...))
+		if o.operation == operationTokenRefresh {
+			o.tel.tokenRefreshFailures.Add(ctx, 1)
+		}
+	}
+	o.tel.requestDuration.Record(ctx, time.Since(o.start).Seconds(), metric.WithAttributes(attrs...))
+	o.span.End()
+}
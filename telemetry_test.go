@@ -0,0 +1,72 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTelemetry(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("SYS4096"))
+	}))
+	defer ts.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", textToSpeechURL: ts.URL, tokenRefreshURL: ts.URL + "/token"}
+	WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))(az)
+	WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))(az)
+
+	_, err := az.SynthesizeWithContext(context.Background(), VoiceParam{SpeechText: "hi", Voice: "en-US-AvaNeural", Locale: LocaleEnUS}, AudioOutput_riff_8khz_8bit_mono_alaw)
+	assert.NoError(t, err)
+	assert.Error(t, az.refreshToken())
+
+	ended := spans.Ended()
+	if assert.Len(t, ended, 2) {
+		attrs := attribute.NewSet(ended[0].Attributes()...)
+		assert.Equal(t, "azuretts.synthesize", ended[0].Name())
+		v, _ := attrs.Value(attrVoice)
+		assert.Equal(t, "en-US-AvaNeural", v.AsString())
+		v, _ = attrs.Value(attrAudioBytes)
+		assert.Equal(t, int64(7), v.AsInt64())
+		v, _ = attrs.Value(attrStatusCode)
+		assert.Equal(t, int64(200), v.AsInt64())
+		v, _ = attrs.Value(attrAttempt)
+		assert.Equal(t, int64(1), v.AsInt64())
+		assert.Equal(t, "azuretts.token_refresh", ended[1].Name())
+		assert.Len(t, ended[1].Events(), 1, "the error should be recorded on the span")
+	}
+
+	var rm metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &rm))
+	metrics := map[string]metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	assert.Len(t, metrics["azuretts.request.duration"].Data.(metricdata.Histogram[float64]).DataPoints, 2)
+	assert.Len(t, metrics["azuretts.synthesis.time_to_first_byte"].Data.(metricdata.Histogram[float64]).DataPoints, 1)
+	failures := metrics["azuretts.token_refresh.failures"].Data.(metricdata.Sum[int64]).DataPoints
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, int64(1), failures[0].Value)
+	}
+	errors := metrics["azuretts.request.errors"].Data.(metricdata.Sum[int64]).DataPoints
+	if assert.Len(t, errors, 1) {
+		v, _ := errors[0].Attributes.Value(attrErrorType)
+		assert.Equal(t, "401", v.AsString())
+	}
+}
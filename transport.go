@@ -0,0 +1,67 @@
+package azuretexttospeech
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig tunes the connection pool shared by all requests of a client, see NewTransport. Zero fields
+// use the defaults given in their comments.
+type TransportConfig struct {
+	MaxIdleConnsPerHost int           // idle connections kept per endpoint, default 64, negative disables reuse
+	MaxConnsPerHost     int           // connections per endpoint, zero is unlimited
+	IdleConnTimeout     time.Duration // default 90 seconds
+	DialTimeout         time.Duration // default 5 seconds
+	TLSHandshakeTimeout time.Duration // default 5 seconds
+	TLSSessionCacheSize int           // TLS sessions cached for resumption, default 64
+	DisableHTTP2        bool
+}
+
+// Defaults of a TransportConfig.
+const (
+	defaultMaxIdleConnsPerHost = 64
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultDialTimeout         = 5 * time.Second
+	defaultTLSHandshakeTimeout = 5 * time.Second
+	defaultTLSSessionCacheSize = 64
+)
+
+// NewTransport returns a transport configured by cfg. Connections are kept alive and reused across the
+// token, voice list and synthesis endpoints, HTTP/2 is negotiated unless disabled and TLS sessions are
+// resumed on new connections.
+func NewTransport(cfg TransportConfig) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   orDefault(cfg.DialTimeout, defaultDialTimeout),
+		KeepAlive: 30 * time.Second,
+	}
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		ForceAttemptHTTP2:   !cfg.DisableHTTP2,
+		MaxIdleConnsPerHost: orDefault(cfg.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:     cfg.MaxConnsPerHost,
+		IdleConnTimeout:     orDefault(cfg.IdleConnTimeout, defaultIdleConnTimeout),
+		TLSHandshakeTimeout: orDefault(cfg.TLSHandshakeTimeout, defaultTLSHandshakeTimeout),
+		TLSClientConfig: &tls.Config{
+			ClientSessionCache: tls.NewLRUClientSessionCache(orDefault(cfg.TLSSessionCacheSize, defaultTLSSessionCacheSize)),
+		},
+	}
+	if cfg.DisableHTTP2 {
+		// a non-nil empty map disables the automatic HTTP/2 upgrade.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
+
+// defaultHTTPClient is shared by the clients not configured with WithHTTPClient or WithTransport.
+var defaultHTTPClient = &http.Client{Transport: NewTransport(TransportConfig{})}
+
+// httpClient returns the client sending all requests of az.
+func (az *AzureCSTextToSpeech) httpClient() *http.Client {
+	if az.client == nil {
+		return defaultHTTPClient
+	}
+	return az.client
+}
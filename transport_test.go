@@ -0,0 +1,93 @@
+package azuretexttospeech
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newCountingServer returns a TLS test server with HTTP/2 which counts the connections it accepts, and a
+// client of it using a transport configured by cfg.
+func newCountingServer(tb testing.TB, cfg TransportConfig) (*AzureCSTextToSpeech, *atomic.Int32) {
+	var conns atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("SYS49152"))
+	})
+	mux.HandleFunc("/voices", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, voiceListAPIGoodResponse)
+	})
+	mux.HandleFunc("/tts", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("SYS4096"))
+	})
+	ts := httptest.NewUnstartedServer(mux)
+	ts.EnableHTTP2 = true
+	ts.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	ts.StartTLS()
+	tb.Cleanup(ts.Close)
+
+	transport := NewTransport(cfg)
+	transport.TLSClientConfig.RootCAs = x509.NewCertPool()
+	transport.TLSClientConfig.RootCAs.AddCert(ts.Certificate())
+	az := &AzureCSTextToSpeech{
+		tokenRefreshURL:     ts.URL + "/token",
+		voiceServiceListURL: ts.URL + "/voices",
+		textToSpeechURL:     ts.URL + "/tts",
+	}
+	WithHTTPClient(&http.Client{Transport: transport})(az)
+	return az, &conns
+}
+
+func TestTransportReusesConnections(t *testing.T) {
+	for _, cfg := range []TransportConfig{{}, {DisableHTTP2: true}} {
+		az, conns := newCountingServer(t, cfg)
+		ctx := context.Background()
+		assert.NoError(t, az.refreshToken())
+		_, err := az.fetchVoiceList(ctx)
+		assert.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			_, err := az.SynthesizeWithContext(ctx, VoiceParam{SpeechText: "Hello", Voice: "ar-EG-Hoda", Locale: LocaleEnUS}, AudioOutput_riff_8khz_16bit_mono_pcm)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), conns.Load(), "all endpoints share a connection, %+v", cfg)
+	}
+}
+
+func benchmarkSynthesizeParallel(b *testing.B, cfg TransportConfig) {
+	az, conns := newCountingServer(b, cfg)
+	az.accessToken = "SYS49152"
+	param := VoiceParam{SpeechText: "Hello", Voice: "ar-EG-Hoda", Locale: LocaleEnUS}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := az.SynthesizeWithContext(context.Background(), param, AudioOutput_riff_8khz_16bit_mono_pcm); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+	b.ReportMetric(float64(conns.Load()), "conns")
+}
+
+// The benchmarks report the connections opened by the server, which stay far below the number of requests.
+func BenchmarkSynthesizeParallelHTTP2(b *testing.B) {
+	benchmarkSynthesizeParallel(b, TransportConfig{})
+}
+
+func BenchmarkSynthesizeParallelHTTP1(b *testing.B) {
+	benchmarkSynthesizeParallel(b, TransportConfig{DisableHTTP2: true})
+}
+
+func BenchmarkSynthesizeParallelNoKeepAlive(b *testing.B) {
+	benchmarkSynthesizeParallel(b, TransportConfig{DisableHTTP2: true, MaxIdleConnsPerHost: -1})
+}
@@ -0,0 +1,174 @@
+package ttsgrpc
+
+import (
+	"context"
+	"io"
+
+	tts "github.com/WqyJh/azuretexttospeech"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Client synthesizes speech with a TextToSpeech service. Errors are gRPC status errors, see the status package.
+type Client struct {
+	client TextToSpeechClient
+}
+
+var _ tts.Synthesizer = (*Client)(nil)
+
+// NewClient returns a client calling the service on cc. The tenant of a context, see tts.WithTenant, is sent
+// with the calls made with it.
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{client: NewTextToSpeechClient(cc)}
+}
+
+// SynthesizeWithContext implements tts.Synthesizer.
+func (c *Client) SynthesizeWithContext(ctx context.Context, param tts.VoiceParam, audioOutput tts.AudioOutput) ([]byte, error) {
+	result, err := c.SynthesizeResultWithContext(ctx, param, audioOutput)
+	if err != nil {
+		return nil, err
+	}
+	return result.Audio, nil
+}
+
+// SynthesizeResultWithContext implements tts.Synthesizer.
+func (c *Client) SynthesizeResultWithContext(ctx context.Context, param tts.VoiceParam, audioOutput tts.AudioOutput) (*tts.SynthesisResult, error) {
+	return c.synthesize(ctx, &SynthesizeRequest{Input: &SynthesizeRequest_Voice{Voice: fromVoiceParam(param)}, Format: string(audioOutput)})
+}
+
+// SynthesizeSSMLWithContext implements tts.Synthesizer.
+func (c *Client) SynthesizeSSMLWithContext(ctx context.Context, ssml string, audioOutput tts.AudioOutput) ([]byte, error) {
+	result, err := c.SynthesizeSSMLResultWithContext(ctx, ssml, audioOutput)
+	if err != nil {
+		return nil, err
+	}
+	return result.Audio, nil
+}
+
+// SynthesizeSSMLResultWithContext implements tts.Synthesizer.
+func (c *Client) SynthesizeSSMLResultWithContext(ctx context.Context, ssml string, audioOutput tts.AudioOutput) (*tts.SynthesisResult, error) {
+	return c.synthesize(ctx, &SynthesizeRequest{Input: &SynthesizeRequest_Ssml{Ssml: ssml}, Format: string(audioOutput)})
+}
+
+// SynthesizeToWriter is SynthesizeResultWithContext streaming the audio to w as it arrives. The Audio of the
+// result is nil.
+func (c *Client) SynthesizeToWriter(ctx context.Context, param tts.VoiceParam, audioOutput tts.AudioOutput, w io.Writer) (*tts.SynthesisResult, error) {
+	return c.stream(ctx, &SynthesizeRequest{Input: &SynthesizeRequest_Voice{Voice: fromVoiceParam(param)}, Format: string(audioOutput)}, w)
+}
+
+// SynthesizeSSMLToWriter is SynthesizeSSMLResultWithContext streaming the audio to w as it arrives. The Audio of
+// the result is nil.
+func (c *Client) SynthesizeSSMLToWriter(ctx context.Context, ssml string, audioOutput tts.AudioOutput, w io.Writer) (*tts.SynthesisResult, error) {
+	return c.stream(ctx, &SynthesizeRequest{Input: &SynthesizeRequest_Ssml{Ssml: ssml}, Format: string(audioOutput)}, w)
+}
+
+// Voices returns the voices of the region of the service.
+func (c *Client) Voices(ctx context.Context) ([]tts.Voice, error) {
+	resp, err := c.client.ListVoices(outgoingContext(ctx), &ListVoicesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	voices := make([]tts.Voice, 0, len(resp.GetVoices()))
+	for _, v := range resp.GetVoices() {
+		voice := tts.Voice{
+			Name:            v.GetName(),
+			ShortName:       v.GetShortName(),
+			Locale:          tts.Locale(v.GetLocale()),
+			SampleRateHertz: v.GetSampleRateHertz(),
+			StyleList:       v.GetStyleList(),
+			RolePlayList:    v.GetRolePlayList(),
+		}
+		// unknown values of newer services keep the zero value.
+		voice.Gender, _ = tts.GenderString(v.GetGender())
+		voice.VoiceType, _ = tts.VoiceTypeString(v.GetVoiceType())
+		voices = append(voices, voice)
+	}
+	return voices, nil
+}
+
+func (c *Client) synthesize(ctx context.Context, req *SynthesizeRequest) (*tts.SynthesisResult, error) {
+	resp, err := c.client.Synthesize(outgoingContext(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	result := fromMetadata(resp.GetMetadata())
+	result.Audio = resp.GetAudio()
+	return result, nil
+}
+
+func (c *Client) stream(ctx context.Context, req *SynthesizeRequest, w io.Writer) (*tts.SynthesisResult, error) {
+	ctx, cancel := context.WithCancel(outgoingContext(ctx))
+	defer cancel()
+	stream, err := c.client.SynthesizeStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			// the last chunk carries the metadata, so the stream must not end before it.
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if len(chunk.GetAudio()) > 0 {
+			if _, err := w.Write(chunk.GetAudio()); err != nil {
+				return nil, err
+			}
+		}
+		if chunk.GetMetadata() != nil {
+			return fromMetadata(chunk.GetMetadata()), nil
+		}
+	}
+}
+
+// outgoingContext sends the tenant of ctx with the call.
+func outgoingContext(ctx context.Context) context.Context {
+	if tenant := tts.TenantFromContext(ctx); tenant != "" {
+		return metadata.AppendToOutgoingContext(ctx, TenantMetadata, tenant)
+	}
+	return ctx
+}
+
+// fromVoiceParam converts param, whose SpeechText is SSML. The zero Gender cannot be told from GenderMale, so
+// the gender is only sent with a locale; the server takes both from the voice list otherwise.
+func fromVoiceParam(param tts.VoiceParam) *VoiceParam {
+	p := &VoiceParam{
+		Text:        param.SpeechText,
+		Ssml:        true,
+		Voice:       param.Voice,
+		Locale:      string(param.Locale),
+		LexiconUris: param.LexiconURIs,
+		Style:       param.Style,
+		StyleDegree: param.StyleDegree,
+		Role:        param.Role,
+	}
+	if param.Locale != "" {
+		p.Gender = param.Gender.String()
+	}
+	if param.Prosody != nil {
+		p.Prosody = &Prosody{
+			Rate:   string(param.Prosody.Rate),
+			Pitch:  string(param.Prosody.Pitch),
+			Volume: string(param.Prosody.Volume),
+		}
+		for _, c := range param.Prosody.Contour {
+			p.Prosody.Contour = append(p.Prosody.Contour, &ContourPoint{Position: c.Position, Pitch: string(c.Pitch)})
+		}
+	}
+	return p
+}
+
+func fromMetadata(m *SynthesisMetadata) *tts.SynthesisResult {
+	return &tts.SynthesisResult{
+		Format:             tts.AudioOutput(m.GetFormat()),
+		ContentType:        m.GetContentType(),
+		RequestID:          m.GetRequestId(),
+		ServerLatency:      m.GetServerLatency().AsDuration(),
+		FirstByteLatency:   m.GetFirstByteLatency().AsDuration(),
+		BillableCharacters: int(m.GetBillableCharacters()),
+		Duration:           m.GetDuration().AsDuration(),
+		Hedged:             m.GetHedged(),
+	}
+}
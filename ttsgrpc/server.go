@@ -0,0 +1,244 @@
+// Package ttsgrpc serves synthesis over gRPC, see tts.proto for the service definition. Server implements the
+// service with a client of the Speech service, Client implements tts.Synthesizer with a connection to it.
+package ttsgrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative tts.proto
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	tts "github.com/WqyJh/azuretexttospeech"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// TenantMetadata is the metadata key attributing a call to a tenant, see tts.WithTenant.
+const TenantMetadata = "x-tenant"
+
+// maxChunkSize is the largest audio chunk sent by SynthesizeStream.
+const maxChunkSize = 32 << 10
+
+// Server implements TextToSpeechServer with a client of the Speech service.
+type Server struct {
+	UnimplementedTextToSpeechServer
+	az *tts.AzureCSTextToSpeech
+}
+
+var _ TextToSpeechServer = (*Server)(nil)
+
+// NewServer returns a server synthesizing with az. Register it with RegisterTextToSpeechServer.
+func NewServer(az *tts.AzureCSTextToSpeech) *Server {
+	return &Server{az: az}
+}
+
+// Synthesize implements TextToSpeechServer.
+func (s *Server) Synthesize(ctx context.Context, req *SynthesizeRequest) (*SynthesizeResponse, error) {
+	ctx = tenantContext(ctx)
+	format, err := parseFormat(req.GetFormat())
+	if err != nil {
+		return nil, err
+	}
+	var result *tts.SynthesisResult
+	if ssml, ok := req.GetInput().(*SynthesizeRequest_Ssml); ok {
+		result, err = s.az.SynthesizeSSMLResultWithContext(ctx, ssml.Ssml, format)
+	} else {
+		var param tts.VoiceParam
+		if param, err = s.voiceParam(ctx, req.GetVoice()); err != nil {
+			return nil, toStatus(err)
+		}
+		result, err = s.az.SynthesizeResultWithContext(ctx, param, format)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &SynthesizeResponse{Audio: result.Audio, Metadata: toMetadata(result)}, nil
+}
+
+// SynthesizeStream implements TextToSpeechServer.
+func (s *Server) SynthesizeStream(req *SynthesizeRequest, stream TextToSpeech_SynthesizeStreamServer) error {
+	ctx := tenantContext(stream.Context())
+	format, err := parseFormat(req.GetFormat())
+	if err != nil {
+		return err
+	}
+	out := chunkWriter{stream}
+	var result *tts.SynthesisResult
+	if ssml, ok := req.GetInput().(*SynthesizeRequest_Ssml); ok {
+		result, err = s.az.SynthesizeSSMLToWriter(ctx, ssml.Ssml, format, out)
+	} else {
+		var param tts.VoiceParam
+		if param, err = s.voiceParam(ctx, req.GetVoice()); err != nil {
+			return toStatus(err)
+		}
+		result, err = s.az.SynthesizeToWriter(ctx, param, format, out)
+	}
+	if err != nil {
+		return toStatus(err)
+	}
+	return stream.Send(&AudioChunk{Metadata: toMetadata(result)})
+}
+
+// ListVoices implements TextToSpeechServer.
+func (s *Server) ListVoices(ctx context.Context, req *ListVoicesRequest) (*ListVoicesResponse, error) {
+	voices, err := s.az.Voices(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to fetch voice list, %v", err)
+	}
+	resp := &ListVoicesResponse{}
+	for _, v := range voices {
+		if req.GetLocale() != "" && !strings.EqualFold(string(v.Locale), req.GetLocale()) {
+			continue
+		}
+		resp.Voices = append(resp.Voices, &Voice{
+			Name:            v.Name,
+			ShortName:       v.ShortName,
+			Gender:          v.Gender.String(),
+			Locale:          string(v.Locale),
+			SampleRateHertz: v.SampleRateHertz,
+			VoiceType:       v.VoiceType.String(),
+			StyleList:       v.StyleList,
+			RolePlayList:    v.RolePlayList,
+		})
+	}
+	return resp, nil
+}
+
+// chunkWriter sends the audio written to it as chunks of a stream.
+type chunkWriter struct {
+	stream TextToSpeech_SynthesizeStreamServer
+}
+
+func (c chunkWriter) Write(p []byte) (int, error) {
+	for written := 0; written < len(p); {
+		n := min(len(p)-written, maxChunkSize)
+		if err := c.stream.Send(&AudioChunk{Audio: p[written : written+n]}); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return len(p), nil
+}
+
+// tenantContext attributes the requests made with ctx to the tenant of the call.
+func tenantContext(ctx context.Context) context.Context {
+	if tenants := metadata.ValueFromIncomingContext(ctx, TenantMetadata); len(tenants) > 0 && tenants[0] != "" {
+		return tts.WithTenant(ctx, tenants[0])
+	}
+	return ctx
+}
+
+func parseFormat(name string) (tts.AudioOutput, error) {
+	if !slices.Contains(tts.AudioOutputs, tts.AudioOutput(name)) {
+		return "", status.Errorf(codes.InvalidArgument, "unsupported format %q", name)
+	}
+	return tts.AudioOutput(name), nil
+}
+
+// voiceParam converts p to the parameters of the client, looking up the locale and gender of the voice when
+// they are not given. Text is escaped unless it is SSML, as in the HTTP API.
+func (s *Server) voiceParam(ctx context.Context, p *VoiceParam) (tts.VoiceParam, error) {
+	if p.GetText() == "" || p.GetVoice() == "" {
+		return tts.VoiceParam{}, status.Error(codes.InvalidArgument, "text and voice are required")
+	}
+	text := p.GetText()
+	if !p.GetSsml() {
+		var escaped strings.Builder
+		xml.EscapeText(&escaped, []byte(text))
+		text = escaped.String()
+	}
+	param := tts.VoiceParam{
+		SpeechText:  text,
+		Voice:       p.GetVoice(),
+		Locale:      tts.Locale(p.GetLocale()),
+		LexiconURIs: p.GetLexiconUris(),
+		Style:       p.GetStyle(),
+		StyleDegree: p.GetStyleDegree(),
+		Role:        p.GetRole(),
+	}
+	if pr := p.GetProsody(); pr != nil {
+		param.Prosody = &tts.Prosody{
+			Rate:   tts.ProsodyRate(pr.GetRate()),
+			Pitch:  tts.ProsodyPitch(pr.GetPitch()),
+			Volume: tts.ProsodyVolume(pr.GetVolume()),
+		}
+		for _, c := range pr.GetContour() {
+			param.Prosody.Contour = append(param.Prosody.Contour, tts.ContourPoint{Position: c.GetPosition(), Pitch: tts.ProsodyPitch(c.GetPitch())})
+		}
+	}
+	if p.GetGender() != "" {
+		gender, err := tts.GenderString(p.GetGender())
+		if err != nil {
+			return tts.VoiceParam{}, status.Error(codes.InvalidArgument, err.Error())
+		}
+		param.Gender = gender
+	}
+	if p.GetLocale() != "" && p.GetGender() != "" {
+		return param, nil
+	}
+
+	v, err := s.az.LookupVoice(ctx, p.GetVoice())
+	if err != nil {
+		return tts.VoiceParam{}, toStatus(err)
+	}
+	if p.GetLocale() == "" {
+		param.Locale = v.Locale
+	}
+	if p.GetGender() == "" {
+		param.Gender = v.Gender
+	}
+	return param, nil
+}
+
+func toMetadata(result *tts.SynthesisResult) *SynthesisMetadata {
+	return &SynthesisMetadata{
+		Format:             string(result.Format),
+		ContentType:        result.ContentType,
+		RequestId:          result.RequestID,
+		ServerLatency:      durationpb.New(result.ServerLatency),
+		FirstByteLatency:   durationpb.New(result.FirstByteLatency),
+		BillableCharacters: int64(result.BillableCharacters),
+		Duration:           durationpb.New(result.Duration),
+		Hedged:             result.Hedged,
+	}
+}
+
+// toStatus returns the status reporting err to the client.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	var statusErr *tts.StatusError
+	// errors not caused by the request occur on the way to the Speech service, such as a failed token request.
+	code := codes.Unavailable
+	switch {
+	case errors.Is(err, tts.ErrInvalidRequest):
+		code = codes.InvalidArgument
+	case errors.Is(err, tts.ErrQuotaExceeded):
+		code = codes.ResourceExhausted
+	case errors.Is(err, tts.ErrCircuitOpen):
+		code = codes.Unavailable
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.As(err, &statusErr):
+		switch statusErr.StatusCode {
+		case http.StatusBadRequest:
+			code = codes.InvalidArgument
+		case http.StatusTooManyRequests:
+			code = codes.ResourceExhausted
+		default:
+			// authorization and server errors of the Speech service are not the fault of the client.
+			code = codes.Unavailable
+		}
+	}
+	return status.Error(code, fmt.Sprint(err))
+}
@@ -0,0 +1,177 @@
+package ttsgrpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tts "github.com/WqyJh/azuretexttospeech"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const voiceList = `[
+  {"Name": "Microsoft Server Speech Text to Speech Voice (en-US, AvaNeural)", "ShortName": "en-US-AvaNeural", "Gender": "Female", "Locale": "en-US", "VoiceType": "Neural"},
+  {"Name": "Microsoft Server Speech Text to Speech Voice (de-DE, ConradNeural)", "ShortName": "de-DE-ConradNeural", "Gender": "Male", "Locale": "de-DE", "VoiceType": "Neural"}
+]`
+
+// newTestClient returns a Client connected over bufconn to a Server backed by a fake Speech service, which
+// answers with the SSML it received repeated to 100 KiB, and fails documents containing "unavailable".
+// tenants receives the tenant of every synthesis request.
+func newTestClient(t *testing.T, tenants chan<- string) *Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("SYS49152"))
+	})
+	mux.HandleFunc("/voices", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(voiceList))
+	})
+	mux.HandleFunc("/tts", func(w http.ResponseWriter, r *http.Request) {
+		ssml, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(ssml), "unavailable") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-RequestId", "6a4a0b5f")
+		w.Write(bytes.Repeat(ssml, 100<<10/len(ssml)+1)[:100<<10])
+	})
+	azure := httptest.NewServer(mux)
+	t.Cleanup(azure.Close)
+
+	az, err := tts.New("SYS64738", tts.RegionEastUS, tts.WithEndpoints(tts.Endpoints{
+		TextToSpeech: azure.URL + "/tts",
+		TokenRefresh: azure.URL + "/token",
+		VoiceList:    azure.URL + "/voices",
+	}), tts.WithUsageMeter(tts.UsageMeterFunc(func(ctx context.Context, usage tts.Usage) {
+		tenants <- usage.Tenant
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { close(az.TokenRefreshDoneCh) })
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	RegisterTextToSpeechServer(srv, NewServer(az))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn)
+}
+
+func TestSynthesize(t *testing.T) {
+	tenants := make(chan string, 10)
+	c := newTestClient(t, tenants)
+	ctx := tts.WithTenant(context.Background(), "acme")
+	ssml := "<speak version='1.0' xml:lang='en-US'><voice xml:lang='en-US' xml:gender='Female' name='en-US-AvaNeural'>Hello</voice></speak>"
+
+	result, err := c.SynthesizeResultWithContext(ctx, tts.VoiceParam{
+		SpeechText: "Hello",
+		Voice:      "en-US-AvaNeural",
+		Locale:     tts.LocaleEnUS,
+		Gender:     tts.GenderFemale,
+	}, tts.AudioOutput_riff_24khz_16bit_mono_pcm)
+	if assert.NoError(t, err) {
+		assert.Len(t, result.Audio, 100<<10)
+		assert.True(t, bytes.HasPrefix(result.Audio, []byte(ssml)))
+		assert.Equal(t, tts.AudioOutput_riff_24khz_16bit_mono_pcm, result.Format)
+		assert.Equal(t, "6a4a0b5f", result.RequestID)
+		assert.Equal(t, 5, result.BillableCharacters)
+		assert.Equal(t, "acme", <-tenants)
+	}
+
+	var audio bytes.Buffer
+	result, err = c.SynthesizeSSMLToWriter(ctx, ssml, tts.AudioOutput_audio_24khz_48kbitrate_mono_mp3, &audio)
+	if assert.NoError(t, err) {
+		assert.Nil(t, result.Audio)
+		assert.Equal(t, 100<<10, audio.Len())
+		assert.True(t, bytes.HasPrefix(audio.Bytes(), []byte(ssml)))
+		assert.Equal(t, "6a4a0b5f", result.RequestID)
+		assert.Equal(t, "acme", <-tenants)
+	}
+
+	for _, tc := range []struct {
+		ssml   string
+		format tts.AudioOutput
+		code   codes.Code
+	}{
+		{ssml, "wav", codes.InvalidArgument},
+		{strings.Replace(ssml, "Hello", "unavailable", 1), tts.AudioOutput_riff_24khz_16bit_mono_pcm, codes.Unavailable},
+	} {
+		_, err := c.SynthesizeSSMLWithContext(ctx, tc.ssml, tc.format)
+		assert.Equal(t, tc.code, status.Code(err), tc.ssml)
+		_, err = c.SynthesizeSSMLToWriter(ctx, tc.ssml, tc.format, io.Discard)
+		assert.Equal(t, tc.code, status.Code(err), tc.ssml)
+	}
+}
+
+func TestListVoices(t *testing.T) {
+	c := newTestClient(t, make(chan string, 10))
+
+	voices, err := c.Voices(context.Background())
+	if assert.NoError(t, err) && assert.Len(t, voices, 2) {
+		assert.Equal(t, "de-DE-ConradNeural", voices[1].ShortName)
+		assert.Equal(t, tts.GenderMale, voices[1].Gender)
+		assert.Equal(t, tts.LocaleDeDE, voices[1].Locale)
+		assert.Equal(t, tts.VoiceNeural, voices[1].VoiceType)
+	}
+
+	resp, err := c.client.ListVoices(context.Background(), &ListVoicesRequest{Locale: "en-us"})
+	if assert.NoError(t, err) && assert.Len(t, resp.GetVoices(), 1) {
+		assert.Equal(t, "en-US-AvaNeural", resp.GetVoices()[0].GetShortName())
+		assert.Equal(t, "Female", resp.GetVoices()[0].GetGender())
+	}
+}
+
+func TestVoiceLookup(t *testing.T) {
+	c := newTestClient(t, make(chan string, 10))
+
+	// a raw request without locale and gender takes them from the voice list.
+	resp, err := c.client.Synthesize(context.Background(), &SynthesizeRequest{
+		Input:  &SynthesizeRequest_Voice{Voice: &VoiceParam{Text: "Hallo", Voice: "de-DE-ConradNeural"}},
+		Format: string(tts.AudioOutput_riff_24khz_16bit_mono_pcm),
+	})
+	if assert.NoError(t, err) {
+		assert.True(t, bytes.HasPrefix(resp.GetAudio(), []byte("<speak version='1.0' xml:lang='de-DE'><voice xml:lang='de-DE' xml:gender='Male' name='de-DE-ConradNeural'>Hallo</voice></speak>")))
+	}
+
+	// plain text is escaped like in the HTTP API, SSML is kept.
+	resp, err = c.client.Synthesize(context.Background(), &SynthesizeRequest{
+		Input:  &SynthesizeRequest_Voice{Voice: &VoiceParam{Text: "Tom & Jerry <3", Voice: "de-DE-ConradNeural"}},
+		Format: string(tts.AudioOutput_riff_24khz_16bit_mono_pcm),
+	})
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(resp.GetAudio()), ">Tom &amp; Jerry &lt;3</voice>")
+	}
+	audio, err := c.SynthesizeWithContext(context.Background(), tts.VoiceParam{SpeechText: "Hallo<break time='1s'/>", Voice: "de-DE-ConradNeural"}, tts.AudioOutput_riff_24khz_16bit_mono_pcm)
+	if assert.NoError(t, err) {
+		// without a locale, the zero gender is not sent as male.
+		assert.True(t, bytes.HasPrefix(audio, []byte("<speak version='1.0' xml:lang='de-DE'><voice xml:lang='de-DE' xml:gender='Male' name='de-DE-ConradNeural'>Hallo<break time='1s'/></voice></speak>")))
+	}
+	audio, err = c.SynthesizeWithContext(context.Background(), tts.VoiceParam{SpeechText: "Hi", Voice: "en-US-AvaNeural"}, tts.AudioOutput_riff_24khz_16bit_mono_pcm)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(audio), "xml:gender='Female'", "the gender comes from the voice list")
+	}
+
+	_, err = c.client.Synthesize(context.Background(), &SynthesizeRequest{
+		Input:  &SynthesizeRequest_Voice{Voice: &VoiceParam{Text: "Hallo", Voice: "xx-XX-Nobody"}},
+		Format: string(tts.AudioOutput_riff_24khz_16bit_mono_pcm),
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
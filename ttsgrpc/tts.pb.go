@@ -0,0 +1,897 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: tts.proto
+
+package ttsgrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SynthesizeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Input:
+	//
+	//	*SynthesizeRequest_Voice
+	//	*SynthesizeRequest_Ssml
+	Input isSynthesizeRequest_Input `protobuf_oneof:"input"`
+	// format is an audio output format, such as "audio-24khz-48kbitrate-mono-mp3".
+	Format        string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynthesizeRequest) Reset() {
+	*x = SynthesizeRequest{}
+	mi := &file_tts_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesizeRequest) ProtoMessage() {}
+
+func (x *SynthesizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tts_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesizeRequest.ProtoReflect.Descriptor instead.
+func (*SynthesizeRequest) Descriptor() ([]byte, []int) {
+	return file_tts_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SynthesizeRequest) GetInput() isSynthesizeRequest_Input {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *SynthesizeRequest) GetVoice() *VoiceParam {
+	if x != nil {
+		if x, ok := x.Input.(*SynthesizeRequest_Voice); ok {
+			return x.Voice
+		}
+	}
+	return nil
+}
+
+func (x *SynthesizeRequest) GetSsml() string {
+	if x != nil {
+		if x, ok := x.Input.(*SynthesizeRequest_Ssml); ok {
+			return x.Ssml
+		}
+	}
+	return ""
+}
+
+func (x *SynthesizeRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type isSynthesizeRequest_Input interface {
+	isSynthesizeRequest_Input()
+}
+
+type SynthesizeRequest_Voice struct {
+	Voice *VoiceParam `protobuf:"bytes,1,opt,name=voice,proto3,oneof"`
+}
+
+type SynthesizeRequest_Ssml struct {
+	// ssml is a complete SSML document.
+	Ssml string `protobuf:"bytes,2,opt,name=ssml,proto3,oneof"`
+}
+
+func (*SynthesizeRequest_Voice) isSynthesizeRequest_Input() {}
+
+func (*SynthesizeRequest_Ssml) isSynthesizeRequest_Input() {}
+
+// VoiceParam mirrors the VoiceParam of the Go client.
+type VoiceParam struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// text is plain text, escaped by the server like the text of the HTTP API. With ssml set, it is SSML
+	// markup spoken inside the voice element instead.
+	Text          string   `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Voice         string   `protobuf:"bytes,2,opt,name=voice,proto3" json:"voice,omitempty"`
+	Locale        string   `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+	Gender        string   `protobuf:"bytes,4,opt,name=gender,proto3" json:"gender,omitempty"`
+	LexiconUris   []string `protobuf:"bytes,5,rep,name=lexicon_uris,json=lexiconUris,proto3" json:"lexicon_uris,omitempty"`
+	Style         string   `protobuf:"bytes,6,opt,name=style,proto3" json:"style,omitempty"`
+	StyleDegree   float64  `protobuf:"fixed64,7,opt,name=style_degree,json=styleDegree,proto3" json:"style_degree,omitempty"`
+	Role          string   `protobuf:"bytes,8,opt,name=role,proto3" json:"role,omitempty"`
+	Prosody       *Prosody `protobuf:"bytes,9,opt,name=prosody,proto3" json:"prosody,omitempty"`
+	Ssml          bool     `protobuf:"varint,10,opt,name=ssml,proto3" json:"ssml,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoiceParam) Reset() {
+	*x = VoiceParam{}
+	mi := &file_tts_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoiceParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoiceParam) ProtoMessage() {}
+
+func (x *VoiceParam) ProtoReflect() protoreflect.Message {
+	mi := &file_tts_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoiceParam.ProtoReflect.Descriptor instead.
+func (*VoiceParam) Descriptor() ([]byte, []int) {
+	return file_tts_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VoiceParam) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *VoiceParam) GetVoice() string {
+	if x != nil {
+		return x.Voice
+	}
+	return ""
+}
+
+func (x *VoiceParam) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *VoiceParam) GetGender() string {
+	if x != nil {
+		return x.Gender
+	}
+	return ""
+}
+
+func (x *VoiceParam) GetLexiconUris() []string {
+	if x != nil {
+		return x.LexiconUris
+	}
+	return nil
+}
+
+func (x *VoiceParam) GetStyle() string {
+	if x != nil {
+		return x.Style
+	}
+	return ""
+}
+
+func (x *VoiceParam) GetStyleDegree() float64 {
+	if x != nil {
+		return x.StyleDegree
+	}
+	return 0
+}
+
+func (x *VoiceParam) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *VoiceParam) GetProsody() *Prosody {
+	if x != nil {
+		return x.Prosody
+	}
+	return nil
+}
+
+func (x *VoiceParam) GetSsml() bool {
+	if x != nil {
+		return x.Ssml
+	}
+	return false
+}
+
+type Prosody struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rate          string                 `protobuf:"bytes,1,opt,name=rate,proto3" json:"rate,omitempty"`
+	Pitch         string                 `protobuf:"bytes,2,opt,name=pitch,proto3" json:"pitch,omitempty"`
+	Volume        string                 `protobuf:"bytes,3,opt,name=volume,proto3" json:"volume,omitempty"`
+	Contour       []*ContourPoint        `protobuf:"bytes,4,rep,name=contour,proto3" json:"contour,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Prosody) Reset() {
+	*x = Prosody{}
+	mi := &file_tts_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Prosody) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Prosody) ProtoMessage() {}
+
+func (x *Prosody) ProtoReflect() protoreflect.Message {
+	mi := &file_tts_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Prosody.ProtoReflect.Descriptor instead.
+func (*Prosody) Descriptor() ([]byte, []int) {
+	return file_tts_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Prosody) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *Prosody) GetPitch() string {
+	if x != nil {
+		return x.Pitch
+	}
+	return ""
+}
+
+func (x *Prosody) GetVolume() string {
+	if x != nil {
+		return x.Volume
+	}
+	return ""
+}
+
+func (x *Prosody) GetContour() []*ContourPoint {
+	if x != nil {
+		return x.Contour
+	}
+	return nil
+}
+
+type ContourPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Position      float64                `protobuf:"fixed64,1,opt,name=position,proto3" json:"position,omitempty"`
+	Pitch         string                 `protobuf:"bytes,2,opt,name=pitch,proto3" json:"pitch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContourPoint) Reset() {
+	*x = ContourPoint{}
+	mi := &file_tts_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContourPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContourPoint) ProtoMessage() {}
+
+func (x *ContourPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_tts_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContourPoint.ProtoReflect.Descriptor instead.
+func (*ContourPoint) Descriptor() ([]byte, []int) {
+	return file_tts_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ContourPoint) GetPosition() float64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *ContourPoint) GetPitch() string {
+	if x != nil {
+		return x.Pitch
+	}
+	return ""
+}
+
+// SynthesisMetadata mirrors the SynthesisResult of the Go client, without the audio.
+type SynthesisMetadata struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Format             string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	ContentType        string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	RequestId          string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ServerLatency      *durationpb.Duration   `protobuf:"bytes,4,opt,name=server_latency,json=serverLatency,proto3" json:"server_latency,omitempty"`
+	FirstByteLatency   *durationpb.Duration   `protobuf:"bytes,5,opt,name=first_byte_latency,json=firstByteLatency,proto3" json:"first_byte_latency,omitempty"`
+	BillableCharacters int64                  `protobuf:"varint,6,opt,name=billable_characters,json=billableCharacters,proto3" json:"billable_characters,omitempty"`
+	Duration           *durationpb.Duration   `protobuf:"bytes,7,opt,name=duration,proto3" json:"duration,omitempty"`
+	Hedged             bool                   `protobuf:"varint,8,opt,name=hedged,proto3" json:"hedged,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SynthesisMetadata) Reset() {
+	*x = SynthesisMetadata{}
+	mi := &file_tts_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesisMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesisMetadata) ProtoMessage() {}
+
+func (x *SynthesisMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_tts_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesisMetadata.ProtoReflect.Descriptor instead.
+func (*SynthesisMetadata) Descriptor() ([]byte, []int) {
+	return file_tts_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SynthesisMetadata) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *SynthesisMetadata) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *SynthesisMetadata) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SynthesisMetadata) GetServerLatency() *durationpb.Duration {
+	if x != nil {
+		return x.ServerLatency
+	}
+	return nil
+}
+
+func (x *SynthesisMetadata) GetFirstByteLatency() *durationpb.Duration {
+	if x != nil {
+		return x.FirstByteLatency
+	}
+	return nil
+}
+
+func (x *SynthesisMetadata) GetBillableCharacters() int64 {
+	if x != nil {
+		return x.BillableCharacters
+	}
+	return 0
+}
+
+func (x *SynthesisMetadata) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *SynthesisMetadata) GetHedged() bool {
+	if x != nil {
+		return x.Hedged
+	}
+	return false
+}
+
+type SynthesizeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Audio         []byte                 `protobuf:"bytes,1,opt,name=audio,proto3" json:"audio,omitempty"`
+	Metadata      *SynthesisMetadata     `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynthesizeResponse) Reset() {
+	*x = SynthesizeResponse{}
+	mi := &file_tts_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesizeResponse) ProtoMessage() {}
+
+func (x *SynthesizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tts_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesizeResponse.ProtoReflect.Descriptor instead.
+func (*SynthesizeResponse) Descriptor() ([]byte, []int) {
+	return file_tts_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SynthesizeResponse) GetAudio() []byte {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+func (x *SynthesizeResponse) GetMetadata() *SynthesisMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type AudioChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Audio []byte                 `protobuf:"bytes,1,opt,name=audio,proto3" json:"audio,omitempty"`
+	// metadata is set on the last chunk only.
+	Metadata      *SynthesisMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AudioChunk) Reset() {
+	*x = AudioChunk{}
+	mi := &file_tts_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AudioChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioChunk) ProtoMessage() {}
+
+func (x *AudioChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_tts_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioChunk.ProtoReflect.Descriptor instead.
+func (*AudioChunk) Descriptor() ([]byte, []int) {
+	return file_tts_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AudioChunk) GetAudio() []byte {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+func (x *AudioChunk) GetMetadata() *SynthesisMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type ListVoicesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// locale filters the voices by locale, case-insensitively. Empty returns all voices.
+	Locale        string `protobuf:"bytes,1,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVoicesRequest) Reset() {
+	*x = ListVoicesRequest{}
+	mi := &file_tts_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVoicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVoicesRequest) ProtoMessage() {}
+
+func (x *ListVoicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tts_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVoicesRequest.ProtoReflect.Descriptor instead.
+func (*ListVoicesRequest) Descriptor() ([]byte, []int) {
+	return file_tts_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListVoicesRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type ListVoicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Voices        []*Voice               `protobuf:"bytes,1,rep,name=voices,proto3" json:"voices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVoicesResponse) Reset() {
+	*x = ListVoicesResponse{}
+	mi := &file_tts_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVoicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVoicesResponse) ProtoMessage() {}
+
+func (x *ListVoicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tts_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVoicesResponse.ProtoReflect.Descriptor instead.
+func (*ListVoicesResponse) Descriptor() ([]byte, []int) {
+	return file_tts_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListVoicesResponse) GetVoices() []*Voice {
+	if x != nil {
+		return x.Voices
+	}
+	return nil
+}
+
+type Voice struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ShortName       string                 `protobuf:"bytes,2,opt,name=short_name,json=shortName,proto3" json:"short_name,omitempty"`
+	Gender          string                 `protobuf:"bytes,3,opt,name=gender,proto3" json:"gender,omitempty"`
+	Locale          string                 `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+	SampleRateHertz string                 `protobuf:"bytes,5,opt,name=sample_rate_hertz,json=sampleRateHertz,proto3" json:"sample_rate_hertz,omitempty"`
+	VoiceType       string                 `protobuf:"bytes,6,opt,name=voice_type,json=voiceType,proto3" json:"voice_type,omitempty"`
+	StyleList       []string               `protobuf:"bytes,7,rep,name=style_list,json=styleList,proto3" json:"style_list,omitempty"`
+	RolePlayList    []string               `protobuf:"bytes,8,rep,name=role_play_list,json=rolePlayList,proto3" json:"role_play_list,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Voice) Reset() {
+	*x = Voice{}
+	mi := &file_tts_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Voice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Voice) ProtoMessage() {}
+
+func (x *Voice) ProtoReflect() protoreflect.Message {
+	mi := &file_tts_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Voice.ProtoReflect.Descriptor instead.
+func (*Voice) Descriptor() ([]byte, []int) {
+	return file_tts_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Voice) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Voice) GetShortName() string {
+	if x != nil {
+		return x.ShortName
+	}
+	return ""
+}
+
+func (x *Voice) GetGender() string {
+	if x != nil {
+		return x.Gender
+	}
+	return ""
+}
+
+func (x *Voice) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *Voice) GetSampleRateHertz() string {
+	if x != nil {
+		return x.SampleRateHertz
+	}
+	return ""
+}
+
+func (x *Voice) GetVoiceType() string {
+	if x != nil {
+		return x.VoiceType
+	}
+	return ""
+}
+
+func (x *Voice) GetStyleList() []string {
+	if x != nil {
+		return x.StyleList
+	}
+	return nil
+}
+
+func (x *Voice) GetRolePlayList() []string {
+	if x != nil {
+		return x.RolePlayList
+	}
+	return nil
+}
+
+var File_tts_proto protoreflect.FileDescriptor
+
+const file_tts_proto_rawDesc = "" +
+	"\n" +
+	"\ttts.proto\x12\x14azuretexttospeech.v1\x1a\x1egoogle/protobuf/duration.proto\"\x84\x01\n" +
+	"\x11SynthesizeRequest\x128\n" +
+	"\x05voice\x18\x01 \x01(\v2 .azuretexttospeech.v1.VoiceParamH\x00R\x05voice\x12\x14\n" +
+	"\x04ssml\x18\x02 \x01(\tH\x00R\x04ssml\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06formatB\a\n" +
+	"\x05input\"\xa3\x02\n" +
+	"\n" +
+	"VoiceParam\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x14\n" +
+	"\x05voice\x18\x02 \x01(\tR\x05voice\x12\x16\n" +
+	"\x06locale\x18\x03 \x01(\tR\x06locale\x12\x16\n" +
+	"\x06gender\x18\x04 \x01(\tR\x06gender\x12!\n" +
+	"\flexicon_uris\x18\x05 \x03(\tR\vlexiconUris\x12\x14\n" +
+	"\x05style\x18\x06 \x01(\tR\x05style\x12!\n" +
+	"\fstyle_degree\x18\a \x01(\x01R\vstyleDegree\x12\x12\n" +
+	"\x04role\x18\b \x01(\tR\x04role\x127\n" +
+	"\aprosody\x18\t \x01(\v2\x1d.azuretexttospeech.v1.ProsodyR\aprosody\x12\x12\n" +
+	"\x04ssml\x18\n" +
+	" \x01(\bR\x04ssml\"\x89\x01\n" +
+	"\aProsody\x12\x12\n" +
+	"\x04rate\x18\x01 \x01(\tR\x04rate\x12\x14\n" +
+	"\x05pitch\x18\x02 \x01(\tR\x05pitch\x12\x16\n" +
+	"\x06volume\x18\x03 \x01(\tR\x06volume\x12<\n" +
+	"\acontour\x18\x04 \x03(\v2\".azuretexttospeech.v1.ContourPointR\acontour\"@\n" +
+	"\fContourPoint\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x01R\bposition\x12\x14\n" +
+	"\x05pitch\x18\x02 \x01(\tR\x05pitch\"\xf8\x02\n" +
+	"\x11SynthesisMetadata\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12@\n" +
+	"\x0eserver_latency\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\rserverLatency\x12G\n" +
+	"\x12first_byte_latency\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x10firstByteLatency\x12/\n" +
+	"\x13billable_characters\x18\x06 \x01(\x03R\x12billableCharacters\x125\n" +
+	"\bduration\x18\a \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x16\n" +
+	"\x06hedged\x18\b \x01(\bR\x06hedged\"o\n" +
+	"\x12SynthesizeResponse\x12\x14\n" +
+	"\x05audio\x18\x01 \x01(\fR\x05audio\x12C\n" +
+	"\bmetadata\x18\x02 \x01(\v2'.azuretexttospeech.v1.SynthesisMetadataR\bmetadata\"g\n" +
+	"\n" +
+	"AudioChunk\x12\x14\n" +
+	"\x05audio\x18\x01 \x01(\fR\x05audio\x12C\n" +
+	"\bmetadata\x18\x02 \x01(\v2'.azuretexttospeech.v1.SynthesisMetadataR\bmetadata\"+\n" +
+	"\x11ListVoicesRequest\x12\x16\n" +
+	"\x06locale\x18\x01 \x01(\tR\x06locale\"I\n" +
+	"\x12ListVoicesResponse\x123\n" +
+	"\x06voices\x18\x01 \x03(\v2\x1b.azuretexttospeech.v1.VoiceR\x06voices\"\xfa\x01\n" +
+	"\x05Voice\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"short_name\x18\x02 \x01(\tR\tshortName\x12\x16\n" +
+	"\x06gender\x18\x03 \x01(\tR\x06gender\x12\x16\n" +
+	"\x06locale\x18\x04 \x01(\tR\x06locale\x12*\n" +
+	"\x11sample_rate_hertz\x18\x05 \x01(\tR\x0fsampleRateHertz\x12\x1d\n" +
+	"\n" +
+	"voice_type\x18\x06 \x01(\tR\tvoiceType\x12\x1d\n" +
+	"\n" +
+	"style_list\x18\a \x03(\tR\tstyleList\x12$\n" +
+	"\x0erole_play_list\x18\b \x03(\tR\frolePlayList2\xb1\x02\n" +
+	"\fTextToSpeech\x12_\n" +
+	"\n" +
+	"Synthesize\x12'.azuretexttospeech.v1.SynthesizeRequest\x1a(.azuretexttospeech.v1.SynthesizeResponse\x12_\n" +
+	"\x10SynthesizeStream\x12'.azuretexttospeech.v1.SynthesizeRequest\x1a .azuretexttospeech.v1.AudioChunk0\x01\x12_\n" +
+	"\n" +
+	"ListVoices\x12'.azuretexttospeech.v1.ListVoicesRequest\x1a(.azuretexttospeech.v1.ListVoicesResponseB,Z*github.com/WqyJh/azuretexttospeech/ttsgrpcb\x06proto3"
+
+var (
+	file_tts_proto_rawDescOnce sync.Once
+	file_tts_proto_rawDescData []byte
+)
+
+func file_tts_proto_rawDescGZIP() []byte {
+	file_tts_proto_rawDescOnce.Do(func() {
+		file_tts_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tts_proto_rawDesc), len(file_tts_proto_rawDesc)))
+	})
+	return file_tts_proto_rawDescData
+}
+
+var file_tts_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_tts_proto_goTypes = []any{
+	(*SynthesizeRequest)(nil),   // 0: azuretexttospeech.v1.SynthesizeRequest
+	(*VoiceParam)(nil),          // 1: azuretexttospeech.v1.VoiceParam
+	(*Prosody)(nil),             // 2: azuretexttospeech.v1.Prosody
+	(*ContourPoint)(nil),        // 3: azuretexttospeech.v1.ContourPoint
+	(*SynthesisMetadata)(nil),   // 4: azuretexttospeech.v1.SynthesisMetadata
+	(*SynthesizeResponse)(nil),  // 5: azuretexttospeech.v1.SynthesizeResponse
+	(*AudioChunk)(nil),          // 6: azuretexttospeech.v1.AudioChunk
+	(*ListVoicesRequest)(nil),   // 7: azuretexttospeech.v1.ListVoicesRequest
+	(*ListVoicesResponse)(nil),  // 8: azuretexttospeech.v1.ListVoicesResponse
+	(*Voice)(nil),               // 9: azuretexttospeech.v1.Voice
+	(*durationpb.Duration)(nil), // 10: google.protobuf.Duration
+}
+var file_tts_proto_depIdxs = []int32{
+	1,  // 0: azuretexttospeech.v1.SynthesizeRequest.voice:type_name -> azuretexttospeech.v1.VoiceParam
+	2,  // 1: azuretexttospeech.v1.VoiceParam.prosody:type_name -> azuretexttospeech.v1.Prosody
+	3,  // 2: azuretexttospeech.v1.Prosody.contour:type_name -> azuretexttospeech.v1.ContourPoint
+	10, // 3: azuretexttospeech.v1.SynthesisMetadata.server_latency:type_name -> google.protobuf.Duration
+	10, // 4: azuretexttospeech.v1.SynthesisMetadata.first_byte_latency:type_name -> google.protobuf.Duration
+	10, // 5: azuretexttospeech.v1.SynthesisMetadata.duration:type_name -> google.protobuf.Duration
+	4,  // 6: azuretexttospeech.v1.SynthesizeResponse.metadata:type_name -> azuretexttospeech.v1.SynthesisMetadata
+	4,  // 7: azuretexttospeech.v1.AudioChunk.metadata:type_name -> azuretexttospeech.v1.SynthesisMetadata
+	9,  // 8: azuretexttospeech.v1.ListVoicesResponse.voices:type_name -> azuretexttospeech.v1.Voice
+	0,  // 9: azuretexttospeech.v1.TextToSpeech.Synthesize:input_type -> azuretexttospeech.v1.SynthesizeRequest
+	0,  // 10: azuretexttospeech.v1.TextToSpeech.SynthesizeStream:input_type -> azuretexttospeech.v1.SynthesizeRequest
+	7,  // 11: azuretexttospeech.v1.TextToSpeech.ListVoices:input_type -> azuretexttospeech.v1.ListVoicesRequest
+	5,  // 12: azuretexttospeech.v1.TextToSpeech.Synthesize:output_type -> azuretexttospeech.v1.SynthesizeResponse
+	6,  // 13: azuretexttospeech.v1.TextToSpeech.SynthesizeStream:output_type -> azuretexttospeech.v1.AudioChunk
+	8,  // 14: azuretexttospeech.v1.TextToSpeech.ListVoices:output_type -> azuretexttospeech.v1.ListVoicesResponse
+	12, // [12:15] is the sub-list for method output_type
+	9,  // [9:12] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_tts_proto_init() }
+func file_tts_proto_init() {
+	if File_tts_proto != nil {
+		return
+	}
+	file_tts_proto_msgTypes[0].OneofWrappers = []any{
+		(*SynthesizeRequest_Voice)(nil),
+		(*SynthesizeRequest_Ssml)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tts_proto_rawDesc), len(file_tts_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tts_proto_goTypes,
+		DependencyIndexes: file_tts_proto_depIdxs,
+		MessageInfos:      file_tts_proto_msgTypes,
+	}.Build()
+	File_tts_proto = out.File
+	file_tts_proto_goTypes = nil
+	file_tts_proto_depIdxs = nil
+}
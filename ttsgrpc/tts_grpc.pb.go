@@ -0,0 +1,213 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: tts.proto
+
+package ttsgrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TextToSpeech_Synthesize_FullMethodName       = "/azuretexttospeech.v1.TextToSpeech/Synthesize"
+	TextToSpeech_SynthesizeStream_FullMethodName = "/azuretexttospeech.v1.TextToSpeech/SynthesizeStream"
+	TextToSpeech_ListVoices_FullMethodName       = "/azuretexttospeech.v1.TextToSpeech/ListVoices"
+)
+
+// TextToSpeechClient is the client API for TextToSpeech service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TextToSpeech synthesizes speech with the Azure Speech service.
+type TextToSpeechClient interface {
+	// Synthesize returns the audio of a request in a single message.
+	Synthesize(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (*SynthesizeResponse, error)
+	// SynthesizeStream returns the audio of a request in chunks as it arrives from the service. The last chunk
+	// carries the metadata of the synthesis.
+	SynthesizeStream(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AudioChunk], error)
+	// ListVoices returns the voices of the region.
+	ListVoices(ctx context.Context, in *ListVoicesRequest, opts ...grpc.CallOption) (*ListVoicesResponse, error)
+}
+
+type textToSpeechClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTextToSpeechClient(cc grpc.ClientConnInterface) TextToSpeechClient {
+	return &textToSpeechClient{cc}
+}
+
+func (c *textToSpeechClient) Synthesize(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (*SynthesizeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SynthesizeResponse)
+	err := c.cc.Invoke(ctx, TextToSpeech_Synthesize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *textToSpeechClient) SynthesizeStream(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AudioChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TextToSpeech_ServiceDesc.Streams[0], TextToSpeech_SynthesizeStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SynthesizeRequest, AudioChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TextToSpeech_SynthesizeStreamClient = grpc.ServerStreamingClient[AudioChunk]
+
+func (c *textToSpeechClient) ListVoices(ctx context.Context, in *ListVoicesRequest, opts ...grpc.CallOption) (*ListVoicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVoicesResponse)
+	err := c.cc.Invoke(ctx, TextToSpeech_ListVoices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TextToSpeechServer is the server API for TextToSpeech service.
+// All implementations must embed UnimplementedTextToSpeechServer
+// for forward compatibility.
+//
+// TextToSpeech synthesizes speech with the Azure Speech service.
+type TextToSpeechServer interface {
+	// Synthesize returns the audio of a request in a single message.
+	Synthesize(context.Context, *SynthesizeRequest) (*SynthesizeResponse, error)
+	// SynthesizeStream returns the audio of a request in chunks as it arrives from the service. The last chunk
+	// carries the metadata of the synthesis.
+	SynthesizeStream(*SynthesizeRequest, grpc.ServerStreamingServer[AudioChunk]) error
+	// ListVoices returns the voices of the region.
+	ListVoices(context.Context, *ListVoicesRequest) (*ListVoicesResponse, error)
+	mustEmbedUnimplementedTextToSpeechServer()
+}
+
+// UnimplementedTextToSpeechServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTextToSpeechServer struct{}
+
+func (UnimplementedTextToSpeechServer) Synthesize(context.Context, *SynthesizeRequest) (*SynthesizeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Synthesize not implemented")
+}
+func (UnimplementedTextToSpeechServer) SynthesizeStream(*SynthesizeRequest, grpc.ServerStreamingServer[AudioChunk]) error {
+	return status.Error(codes.Unimplemented, "method SynthesizeStream not implemented")
+}
+func (UnimplementedTextToSpeechServer) ListVoices(context.Context, *ListVoicesRequest) (*ListVoicesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListVoices not implemented")
+}
+func (UnimplementedTextToSpeechServer) mustEmbedUnimplementedTextToSpeechServer() {}
+func (UnimplementedTextToSpeechServer) testEmbeddedByValue()                      {}
+
+// UnsafeTextToSpeechServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TextToSpeechServer will
+// result in compilation errors.
+type UnsafeTextToSpeechServer interface {
+	mustEmbedUnimplementedTextToSpeechServer()
+}
+
+func RegisterTextToSpeechServer(s grpc.ServiceRegistrar, srv TextToSpeechServer) {
+	// If the following call panics, it indicates UnimplementedTextToSpeechServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TextToSpeech_ServiceDesc, srv)
+}
+
+func _TextToSpeech_Synthesize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SynthesizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextToSpeechServer).Synthesize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TextToSpeech_Synthesize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextToSpeechServer).Synthesize(ctx, req.(*SynthesizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TextToSpeech_SynthesizeStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SynthesizeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TextToSpeechServer).SynthesizeStream(m, &grpc.GenericServerStream[SynthesizeRequest, AudioChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TextToSpeech_SynthesizeStreamServer = grpc.ServerStreamingServer[AudioChunk]
+
+func _TextToSpeech_ListVoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVoicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextToSpeechServer).ListVoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TextToSpeech_ListVoices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextToSpeechServer).ListVoices(ctx, req.(*ListVoicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TextToSpeech_ServiceDesc is the grpc.ServiceDesc for TextToSpeech service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TextToSpeech_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "azuretexttospeech.v1.TextToSpeech",
+	HandlerType: (*TextToSpeechServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Synthesize",
+			Handler:    _TextToSpeech_Synthesize_Handler,
+		},
+		{
+			MethodName: "ListVoices",
+			Handler:    _TextToSpeech_ListVoices_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SynthesizeStream",
+			Handler:       _TextToSpeech_SynthesizeStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "tts.proto",
+}
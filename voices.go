@@ -1,61 +1,139 @@
 package azuretexttospeech
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // voiceListAPI is the source for supported voice list to region mapping
 // See: https://docs.microsoft.com/en-us/azure/cognitive-services/speech-service/rest-text-to-speech#regions-and-endpoints
 const voiceListAPI = "https://%s.tts.speech.microsoft.com/cognitiveservices/voices/list"
 
-//go:generate enumer -type=voiceType -linecomment -json
-type voiceType int
+// VoiceType is the tier of a voice, which determines its price.
+//
+//go:generate enumer -type=VoiceType -linecomment -json
+type VoiceType int
 
 const (
-	voiceStandard voiceType = iota // Standard
-	voiceNeural                    // Neural
+	VoiceStandard VoiceType = iota // Standard
+	VoiceNeural                    // Neural
 )
 
-type regionVoiceListResponse struct {
+// Voice describes a voice available in the region of a client, see Voices.
+type Voice struct {
 	Name            string    `json:"Name"`
 	ShortName       string    `json:"ShortName"`
 	Gender          Gender    `json:"Gender"`
 	Locale          Locale    `json:"Locale"`
 	SampleRateHertz string    `json:"SampleRateHertz"`
-	VoiceType       voiceType `json:"VoiceType"`
+	VoiceType       VoiceType `json:"VoiceType"`
+	StyleList       []string  `json:"StyleList"`
+	RolePlayList    []string  `json:"RolePlayList"`
 }
 
-func (az *AzureCSTextToSpeech) fetchVoiceList() ([]regionVoiceListResponse, error) {
+func (az *AzureCSTextToSpeech) fetchVoiceList(ctx context.Context) (_ []Voice, err error) {
+	ctx, op := az.telemetry().startOperation(ctx, operationListVoices)
+	defer func() { op.end(ctx, err) }()
 
-	request, err := http.NewRequest(http.MethodGet, az.voiceServiceListURL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, az.voiceServiceListURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	request.Header.Set("Authorization", "Bearer "+az.accessToken)
-	response, err := az.client.Do(request)
+	request.Header.Set("Authorization", "Bearer "+az.token())
+	response, err := az.do(request)
 	if err != nil {
 		return nil, err
 	}
 	defer response.Body.Close()
+	op.status = response.StatusCode
 
 	switch response.StatusCode {
 	case http.StatusOK:
-		var r []regionVoiceListResponse
+		var r []Voice
 		if err := json.NewDecoder(response.Body).Decode(&r); err != nil {
 			return nil, fmt.Errorf("unable to decode voice list response body, %v", err)
 		}
 		return r, nil
 	case http.StatusBadRequest:
-		return nil, fmt.Errorf("%d - A required parameter is missing, empty, or null. Or, the value passed to either a required or optional parameter is invalid. A common issue is a header that is too long", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "A required parameter is missing, empty, or null. Or, the value passed to either a required or optional parameter is invalid. A common issue is a header that is too long"}
 	case http.StatusUnauthorized:
-		return nil, fmt.Errorf("%d - The request is not authorized. Check to make sure your subscription key or token is valid and in the correct region", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "The request is not authorized. Check to make sure your subscription key or token is valid and in the correct region"}
 	case http.StatusTooManyRequests:
-		return nil, fmt.Errorf("%d - You have exceeded the quota or rate of requests allowed for your subscription", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "You have exceeded the quota or rate of requests allowed for your subscription"}
 	case http.StatusBadGateway:
-		return nil, fmt.Errorf("%d - Network or server-side issue. May also indicate invalid headers", response.StatusCode)
+		return nil, &StatusError{StatusCode: response.StatusCode, Message: "Network or server-side issue. May also indicate invalid headers"}
 	}
-	return nil, fmt.Errorf("%d - unexpected response code from voice list API", response.StatusCode)
+	return nil, &StatusError{StatusCode: response.StatusCode, Message: "unexpected response code from voice list API"}
+}
+
+// Voices returns the voices available in the region of the client. The list is fetched on first use and then
+// reused, Verify refreshes it.
+func (az *AzureCSTextToSpeech) Voices(ctx context.Context) ([]Voice, error) {
+	voices, err := az.voiceCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]Voice(nil), voices...), nil
+}
+
+// voiceCatalog returns the voice list of the client's region. The list is fetched once and then reused.
+func (az *AzureCSTextToSpeech) voiceCatalog(ctx context.Context) ([]Voice, error) {
+	az.voicesMu.Lock()
+	defer az.voicesMu.Unlock()
+	if az.voices != nil {
+		return az.voices, nil
+	}
+	voices, err := az.fetchVoiceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	az.voices = voices
+	return voices, nil
+}
+
+// validateVoiceStyle checks that the style and role requested in param are supported by its voice.
+func (az *AzureCSTextToSpeech) validateVoiceStyle(ctx context.Context, param VoiceParam) error {
+	if param.Style == "" && param.Role == "" {
+		return nil
+	}
+	v, err := az.LookupVoice(ctx, param.Voice)
+	if err != nil {
+		return err
+	}
+	if param.Style != "" && !containsFold(v.StyleList, param.Style) {
+		return invalidRequest(fmt.Errorf("voice %s does not support style %q, supported styles are %v", param.Voice, param.Style, v.StyleList))
+	}
+	if param.Role != "" && !containsFold(v.RolePlayList, param.Role) {
+		return invalidRequest(fmt.Errorf("voice %s does not support role %q, supported roles are %v", param.Voice, param.Role, v.RolePlayList))
+	}
+	return nil
+}
+
+// LookupVoice returns the voice of the region named name, either its short name, such as "en-US-AvaNeural",
+// or its full name. The error wraps ErrInvalidRequest when the region has no such voice. Use it to complete
+// the Locale and Gender of a VoiceParam.
+func (az *AzureCSTextToSpeech) LookupVoice(ctx context.Context, name string) (Voice, error) {
+	voices, err := az.voiceCatalog(ctx)
+	if err != nil {
+		return Voice{}, fmt.Errorf("failed to fetch voice list, %w", err)
+	}
+	for _, v := range voices {
+		if v.ShortName == name || v.Name == name {
+			return v, nil
+		}
+	}
+	return Voice{}, invalidRequest(fmt.Errorf("voice %s is not available in this region", name))
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
 }
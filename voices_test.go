@@ -1,6 +1,7 @@
 package azuretexttospeech
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -22,7 +23,7 @@ func TestFetchVoiceList(t *testing.T) {
 		voiceServiceListURL: ts.URL,
 		client:              &http.Client{},
 	}
-	vl, err := az.fetchVoiceList()
+	vl, err := az.fetchVoiceList(context.Background())
 	if err != nil {
 		t.Errorf("received error %v", err)
 	}
@@ -30,6 +31,86 @@ func TestFetchVoiceList(t *testing.T) {
 
 }
 
+func TestValidateVoiceStyle(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, voiceListAPIStyleResponse)
+	}))
+	defer ts.Close()
+
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", voiceServiceListURL: ts.URL, client: &http.Client{}}
+	ctx := context.Background()
+	tests := []struct {
+		param VoiceParam
+		valid bool
+	}{
+		{VoiceParam{Voice: "zh-CN-XiaomoNeural"}, true},
+		{VoiceParam{Voice: "zh-CN-XiaomoNeural", Style: "Sad", Role: "Boy"}, true},
+		{VoiceParam{Voice: "zh-CN-XiaomoNeural", Style: "whispering"}, false},
+		{VoiceParam{Voice: "zh-CN-XiaomoNeural", Role: "OlderAdultMale"}, false},
+		{VoiceParam{Voice: "ar-EG-SalmaNeural", Style: "sad"}, false},
+		{VoiceParam{Voice: "xx-XX-Nobody", Style: "sad"}, false},
+	}
+	for _, tt := range tests {
+		err := az.validateVoiceStyle(ctx, tt.param)
+		if tt.valid {
+			assert.NoError(t, err, "%+v", tt.param)
+		} else {
+			assert.Error(t, err, "%+v", tt.param)
+		}
+	}
+	assert.Equal(t, 1, requests, "voice list should be fetched once")
+}
+
+func TestLookupVoice(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, voiceListAPIStyleResponse)
+	}))
+	defer ts.Close()
+
+	az := &AzureCSTextToSpeech{accessToken: "SYS49152", voiceServiceListURL: ts.URL, client: &http.Client{}}
+	ctx := context.Background()
+	for _, name := range []string{"ar-EG-SalmaNeural", "Microsoft Server Speech Text to Speech Voice (ar-EG, SalmaNeural)"} {
+		v, err := az.LookupVoice(ctx, name)
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, "ar-EG-SalmaNeural", v.ShortName)
+			assert.Equal(t, GenderFemale, v.Gender)
+		}
+	}
+	_, err := az.LookupVoice(ctx, "xx-XX-Nobody")
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	ts.Close()
+	az = &AzureCSTextToSpeech{accessToken: "SYS49152", voiceServiceListURL: ts.URL, client: &http.Client{}}
+	_, err = az.LookupVoice(ctx, "ar-EG-SalmaNeural")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidRequest, "failing to fetch the voice list is not the fault of the request")
+}
+
+const voiceListAPIStyleResponse string = `[
+    {
+        "Name": "Microsoft Server Speech Text to Speech Voice (zh-CN, XiaomoNeural)",
+        "ShortName": "zh-CN-XiaomoNeural",
+        "Gender": "Female",
+        "Locale": "zh-CN",
+        "SampleRateHertz": "24000",
+        "VoiceType": "Neural",
+        "StyleList": ["embarrassed", "calm", "fearful", "cheerful", "sad"],
+        "RolePlayList": ["YoungAdultMale", "YoungAdultFemale", "Boy", "Girl"]
+    },
+    {
+        "Name": "Microsoft Server Speech Text to Speech Voice (ar-EG, SalmaNeural)",
+        "ShortName": "ar-EG-SalmaNeural",
+        "Gender": "Female",
+        "Locale": "ar-EG",
+        "SampleRateHertz": "24000",
+        "VoiceType": "Neural"
+    }
+]`
+
 // sample response taken from https://docs.microsoft.com/en-us/azure/cognitive-services/speech-service/rest-text-to-speech#sample-response
 const voiceListAPIGoodResponse string = `[
     {
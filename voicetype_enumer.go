@@ -1,4 +1,4 @@
-// Code generated by "enumer -type=voiceType -linecomment -json"; DO NOT EDIT.
+// Code generated by "enumer -type=VoiceType -linecomment -json"; DO NOT EDIT.
 
 package azuretexttospeech
 
@@ -8,69 +8,72 @@ import (
 	"strings"
 )
 
-const _voiceTypeName = "StandardNeural"
+const _VoiceTypeName = "StandardNeural"
 
-var _voiceTypeIndex = [...]uint8{0, 8, 14}
+var _VoiceTypeIndex = [...]uint8{0, 8, 14}
 
-const _voiceTypeLowerName = "standardneural"
+const _VoiceTypeLowerName = "standardneural"
 
-func (i voiceType) String() string {
-	if i < 0 || i >= voiceType(len(_voiceTypeIndex)-1) {
-		return fmt.Sprintf("voiceType(%d)", i)
+func (i VoiceType) String() string {
+	if i < 0 || i >= VoiceType(len(_VoiceTypeIndex)-1) {
+		return fmt.Sprintf("VoiceType(%d)", i)
 	}
-	return _voiceTypeName[_voiceTypeIndex[i]:_voiceTypeIndex[i+1]]
+	return _VoiceTypeName[_VoiceTypeIndex[i]:_VoiceTypeIndex[i+1]]
 }
 
 // An "invalid array index" compiler error signifies that the constant values have changed.
 // Re-run the stringer command to generate them again.
-func _voiceTypeNoOp() {
+func _VoiceTypeNoOp() {
 	var x [1]struct{}
-	_ = x[voiceStandard-(0)]
-	_ = x[voiceNeural-(1)]
+	_ = x[VoiceStandard-(0)]
+	_ = x[VoiceNeural-(1)]
 }
 
-var _voiceTypeValues = []voiceType{voiceStandard, voiceNeural}
+var _VoiceTypeValues = []VoiceType{VoiceStandard, VoiceNeural}
 
-var _voiceTypeNameToValueMap = map[string]voiceType{
-	_voiceTypeName[0:8]:       voiceStandard,
-	_voiceTypeLowerName[0:8]:  voiceStandard,
-	_voiceTypeName[8:14]:      voiceNeural,
-	_voiceTypeLowerName[8:14]: voiceNeural,
+var _VoiceTypeNameToValueMap = map[string]VoiceType{
+	_VoiceTypeName[0:8]:  VoiceStandard,
+	_VoiceTypeName[8:14]: VoiceNeural,
 }
 
-var _voiceTypeNames = []string{
-	_voiceTypeName[0:8],
-	_voiceTypeName[8:14],
+var _VoiceTypeLowerNameToValueMap = map[string]VoiceType{
+	_VoiceTypeLowerName[0:8]:  VoiceStandard,
+	_VoiceTypeLowerName[8:14]: VoiceNeural,
 }
 
-// voiceTypeString retrieves an enum value from the enum constants string name.
+var _VoiceTypeNames = []string{
+	_VoiceTypeName[0:8],
+	_VoiceTypeName[8:14],
+}
+
+// VoiceTypeString retrieves an enum value from the enum constants string name.
 // Throws an error if the param is not part of the enum.
-func voiceTypeString(s string) (voiceType, error) {
-	if val, ok := _voiceTypeNameToValueMap[s]; ok {
+func VoiceTypeString(s string) (VoiceType, error) {
+	if val, ok := _VoiceTypeNameToValueMap[s]; ok {
 		return val, nil
 	}
 
-	if val, ok := _voiceTypeNameToValueMap[strings.ToLower(s)]; ok {
+	if val, ok := _VoiceTypeLowerNameToValueMap[strings.ToLower(s)]; ok {
 		return val, nil
 	}
-	return 0, fmt.Errorf("%s does not belong to voiceType values", s)
+	return 0, fmt.Errorf("%s does not belong to VoiceType values", s)
 }
 
-// voiceTypeValues returns all values of the enum
-func voiceTypeValues() []voiceType {
-	return _voiceTypeValues
+// VoiceTypeValues returns all values of the enum
+func VoiceTypeValues() []VoiceType {
+	return _VoiceTypeValues
 }
 
-// voiceTypeStrings returns a slice of all String values of the enum
-func voiceTypeStrings() []string {
-	strs := make([]string, len(_voiceTypeNames))
-	copy(strs, _voiceTypeNames)
+// VoiceTypeStrings returns a slice of all String values of the enum
+func VoiceTypeStrings() []string {
+	strs := make([]string, len(_VoiceTypeNames))
+	copy(strs, _VoiceTypeNames)
 	return strs
 }
 
-// IsAvoiceType returns "true" if the value is listed in the enum definition. "false" otherwise
-func (i voiceType) IsAvoiceType() bool {
-	for _, v := range _voiceTypeValues {
+// IsAVoiceType returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i VoiceType) IsAVoiceType() bool {
+	for _, v := range _VoiceTypeValues {
 		if i == v {
 			return true
 		}
@@ -78,19 +81,19 @@ func (i voiceType) IsAvoiceType() bool {
 	return false
 }
 
-// MarshalJSON implements the json.Marshaler interface for voiceType
-func (i voiceType) MarshalJSON() ([]byte, error) {
+// MarshalJSON implements the json.Marshaler interface for VoiceType
+func (i VoiceType) MarshalJSON() ([]byte, error) {
 	return json.Marshal(i.String())
 }
 
-// UnmarshalJSON implements the json.Unmarshaler interface for voiceType
-func (i *voiceType) UnmarshalJSON(data []byte) error {
+// UnmarshalJSON implements the json.Unmarshaler interface for VoiceType
+func (i *VoiceType) UnmarshalJSON(data []byte) error {
 	var s string
 	if err := json.Unmarshal(data, &s); err != nil {
-		return fmt.Errorf("voiceType should be a string, got %s", data)
+		return fmt.Errorf("VoiceType should be a string, got %s", data)
 	}
 
 	var err error
-	*i, err = voiceTypeString(s)
+	*i, err = VoiceTypeString(s)
 	return err
 }